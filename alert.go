@@ -1,5 +1,10 @@
 package weatherkit
 
+import (
+	"encoding/json"
+	"reflect"
+)
+
 // WeatherAlertRequest requests weather alert details for a specific alert id.
 type WeatherAlertRequest struct {
 	// (Required) The unique identifier for the weather alert.
@@ -9,8 +14,12 @@ type WeatherAlertRequest struct {
 	Language string `json:"language,omitempty"`
 }
 
-func (o WeatherAlertRequest) url() string {
-	return weatherAlertEndpoint(o.Language, o.ID)
+func (o WeatherAlertRequest) endpoint() Endpoint {
+	return EndpointWeatherAlert
+}
+
+func (o WeatherAlertRequest) url(base string) string {
+	return weatherAlertEndpoint(base, o.Language, o.ID)
 }
 
 // WeatherAlertResponse contains an official message indicating severe weather from a reporting agency.
@@ -19,6 +28,46 @@ type WeatherAlertResponse struct {
 	WeatherAlertSummary
 }
 
+// UnmarshalJSON decodes both parts of the response. Fields unknown to either are captured
+// into the Extras of the WeatherAlertSummary.
+func (r *WeatherAlertResponse) UnmarshalJSON(data []byte) error {
+	err := json.Unmarshal(data, &r.WeatherAlertData)
+	if err != nil {
+		return err
+	}
+
+	err = json.Unmarshal(data, &r.WeatherAlertSummary)
+	if err != nil {
+		return err
+	}
+
+	if r.Extras != nil {
+		r.Extras = unknownKeys(r.Extras, reflect.TypeOf(r.WeatherAlertData))
+	}
+
+	return nil
+}
+
+// MarshalJSON encodes both parts of the response as a single object.
+func (r WeatherAlertResponse) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(r.WeatherAlertData)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := map[string]json.RawMessage{}
+
+	encoded, err := json.Marshal(r.WeatherAlertSummary)
+	if err == nil {
+		err = json.Unmarshal(encoded, &summary)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return appendObjectFields(data, unknownKeys(summary, reflect.TypeOf(r.WeatherAlertData)))
+}
+
 // WeatherAlertData is the weather alert information.
 type WeatherAlertData struct {
 	// (Required) An object defining the geographic region the weather alert applies to.
@@ -38,6 +87,66 @@ type EventText struct {
 }
 
 // WeatherAlertArea defines the geographic region the weather alert applies to.
+// It is encoded as a GeoJSON feature collection.
 type WeatherAlertArea struct {
-	// Fields are undocumented.
+	// The features making up the region, usually Polygon or MultiPolygon geometries.
+	Features []Feature
+}
+
+// UnmarshalJSON decodes a GeoJSON feature collection. A single feature or geometry
+// is decoded as a collection of one feature.
+func (a *WeatherAlertArea) UnmarshalJSON(data []byte) error {
+	object := struct {
+		Type     string    `json:"type"`
+		Features []Feature `json:"features"`
+	}{}
+
+	err := json.Unmarshal(data, &object)
+	if err != nil {
+		return err
+	}
+
+	*a = WeatherAlertArea{}
+
+	switch object.Type {
+	case "":
+		return nil
+	case geoJSONFeatureCollection:
+		a.Features = object.Features
+		return nil
+	case geoJSONFeature:
+		feature := Feature{}
+		err = json.Unmarshal(data, &feature)
+		a.Features = []Feature{feature}
+		return err
+	}
+
+	geometry := &Geometry{}
+	err = json.Unmarshal(data, geometry)
+	a.Features = []Feature{{Geometry: geometry}}
+
+	return err
+}
+
+// MarshalJSON encodes the area as a GeoJSON feature collection.
+func (a WeatherAlertArea) MarshalJSON() ([]byte, error) {
+	features := a.Features
+	if features == nil {
+		features = []Feature{}
+	}
+
+	return json.Marshal(struct {
+		Type     string    `json:"type"`
+		Features []Feature `json:"features"`
+	}{geoJSONFeatureCollection, features})
+}
+
+// Polygons returns the polygons of every feature of the area.
+func (a WeatherAlertArea) Polygons() []Polygon {
+	polygons := []Polygon{}
+	for _, feature := range a.Features {
+		polygons = append(polygons, feature.Polygons()...)
+	}
+
+	return polygons
 }
@@ -0,0 +1,152 @@
+package weatherkit
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// DefaultAlertDetailsConcurrency is the number of alerts requested at once when AlertDetailsRequest.Concurrency is not set.
+const DefaultAlertDetailsConcurrency = 4
+
+// AlertDetailsRequest requests the details of every alert of a collection.
+type AlertDetailsRequest struct {
+	// (Required) The alerts to obtain details for, such as WeatherResponse.WeatherAlerts.
+	Alerts *WeatherAlertCollection
+
+	// (Required) The language tag to use for localizing responses.
+	Language string
+
+	// The maximum number of alerts requested at once. Defaults to DefaultAlertDetailsConcurrency.
+	Concurrency int
+
+	// Cache serves the details of each alert by alert ID and language, as they do not change once the
+	// alert is issued. Details are stored until the alert expires, or for the Client CacheTTL when it
+	// carries no future expiration time. Details are not cached when nil.
+	Cache Cache
+}
+
+// ids returns the distinct alert IDs of the collection, in order.
+func (o AlertDetailsRequest) ids() []string {
+	if o.Alerts == nil {
+		return nil
+	}
+
+	seen := map[string]bool{}
+	ids := []string{}
+
+	for _, alert := range o.Alerts.Alerts {
+		if len(alert.ID) < 1 || seen[alert.ID] {
+			continue
+		}
+
+		seen[alert.ID] = true
+		ids = append(ids, alert.ID)
+	}
+
+	return ids
+}
+
+func (o AlertDetailsRequest) concurrency() int {
+	if o.Concurrency > 0 {
+		return o.Concurrency
+	}
+
+	return DefaultAlertDetailsConcurrency
+}
+
+// AlertDetailsError reports the alerts whose details could not be obtained.
+type AlertDetailsError struct {
+	// The error of each failed alert, by alert ID.
+	Errors map[string]error
+}
+
+func (e *AlertDetailsError) Error() string {
+	failures := make([]string, 0, len(e.Errors))
+	for _, id := range sortedKeys(e.Errors) {
+		failures = append(failures, fmt.Sprintf("%s: %s", id, e.Errors[id]))
+	}
+
+	return fmt.Sprintf("failed to get details of %d alerts: %s", len(e.Errors), strings.Join(failures, "; "))
+}
+
+// AlertDetails obtains the details of every alert of the collection concurrently, by alert ID.
+// When some alerts fail, the details of the others are returned along with an *AlertDetailsError.
+// Details are served from the Cache of the request when it is set.
+func (d *CredentialedClient) AlertDetails(ctx context.Context, request AlertDetailsRequest) (map[string]*WeatherAlertResponse, error) {
+	token, err := d.getToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return d.options.client.alertDetails(ctx, d.baseURL(), token, request)
+}
+
+// AlertDetails obtains the details of every alert of the collection concurrently, by alert ID.
+// When some alerts fail, the details of the others are returned along with an *AlertDetailsError.
+// The token parameter is a JWT developer token.
+func (d *Client) AlertDetails(ctx context.Context, token string, request AlertDetailsRequest) (map[string]*WeatherAlertResponse, error) {
+	return d.alertDetails(ctx, d.baseURL(), token, request)
+}
+
+func (d *Client) alertDetails(ctx context.Context, baseURL string, token string, request AlertDetailsRequest) (map[string]*WeatherAlertResponse, error) {
+	ids := request.ids()
+
+	workers := request.concurrency()
+	if workers > len(ids) {
+		workers = len(ids)
+	}
+
+	jobs := make(chan string, len(ids))
+	for _, id := range ids {
+		jobs <- id
+	}
+	close(jobs)
+
+	var mu sync.Mutex
+	details := make(map[string]*WeatherAlertResponse, len(ids))
+	failures := map[string]error{}
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for id := range jobs {
+				response, err := d.detail(ctx, request.Cache, baseURL, token, WeatherAlertRequest{ID: id, Language: request.Language})
+
+				mu.Lock()
+				if err != nil {
+					failures[id] = err
+				} else {
+					details[id] = response
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if len(failures) > 0 {
+		return details, &AlertDetailsError{Errors: failures}
+	}
+
+	return details, nil
+}
+
+// detail requests the details of a single alert from cache, if any, or the API, unless the context is already done.
+func (d *Client) detail(ctx context.Context, cache Cache, baseURL string, token string, request WeatherAlertRequest) (*WeatherAlertResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if cache == nil {
+		return d.alert(ctx, baseURL, token, request)
+	}
+
+	response := WeatherAlertResponse{}
+	err := d.cachedGet(ctx, cache, baseURL, token, request, &response, d.alertExpiry(&response))
+	return &response, err
+}
@@ -0,0 +1,189 @@
+package weatherkit
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCredentialedClientAlertDetails(t *testing.T) {
+	pk, err := createPrivateKeyPEM()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	server, alerts := getMockAlertServer(t)
+	defer server.Close()
+
+	client := NewCredentialedClient(Credentials{
+		KeyID:      "key",
+		TeamID:     "team",
+		ServiceID:  "service",
+		PrivateKey: pk,
+	}, WithBaseURL(server.URL))
+
+	collection := &WeatherAlertCollection{
+		Alerts: []WeatherAlertSummary{{ID: "a"}, {ID: "b"}, {ID: "missing"}, {ID: "a"}, {ID: "c"}, {}},
+	}
+
+	details, err := client.AlertDetails(context.TODO(), AlertDetailsRequest{Alerts: collection, Language: "en", Concurrency: 2})
+
+	detailsErr := &AlertDetailsError{}
+	if !errors.As(err, &detailsErr) {
+		t.Fatalf("expected an AlertDetailsError, got: %v", err)
+	}
+
+	if len(detailsErr.Errors) != 1 || !errors.Is(detailsErr.Errors["missing"], ErrNotFound) {
+		t.Errorf("expected only the missing alert to fail, have: %v", detailsErr.Errors)
+	}
+
+	for _, id := range []string{"a", "b", "c"} {
+		if details[id] == nil || len(details[id].Area.Features) < 1 {
+			t.Errorf("expected the details of %s, have: %+v", id, details[id])
+		}
+	}
+
+	if len(details) != 3 {
+		t.Errorf("want: %d details, have: %d", 3, len(details))
+	}
+
+	if have := alerts.requests(); have != 4 {
+		t.Errorf("want: %d requests, have: %d", 4, have)
+	}
+
+	if alerts.maxInFlight > 2 {
+		t.Errorf("want at most %d concurrent requests, have: %d", 2, alerts.maxInFlight)
+	}
+}
+
+func TestAlertDetailsCache(t *testing.T) {
+	server, alerts := getMockAlertServer(t)
+	defer server.Close()
+
+	client := Client{BaseURL: server.URL}
+	request := AlertDetailsRequest{
+		Alerts:   &WeatherAlertCollection{Alerts: []WeatherAlertSummary{{ID: "a"}, {ID: "b"}}},
+		Language: "en",
+		Cache:    NewMemoryCache(0),
+	}
+
+	for i := 0; i < 2; i++ {
+		details, err := client.AlertDetails(context.TODO(), "", request)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		if len(details) != 2 {
+			t.Errorf("want: %d details, have: %d", 2, len(details))
+		}
+	}
+
+	if have := alerts.requests(); have != 2 {
+		t.Errorf("expected the second call to be cached, want: %d requests, have: %d", 2, have)
+	}
+
+	request.Language = "fr"
+
+	_, err := client.AlertDetails(context.TODO(), "", request)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if have := alerts.requests(); have != 4 {
+		t.Errorf("expected each language to be cached separately, want: %d requests, have: %d", 4, have)
+	}
+}
+
+func TestAlertDetailsCanceled(t *testing.T) {
+	server, alerts := getMockAlertServer(t)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+
+	client := Client{BaseURL: server.URL}
+	request := AlertDetailsRequest{
+		Alerts:   &WeatherAlertCollection{Alerts: []WeatherAlertSummary{{ID: "a"}, {ID: "b"}}},
+		Language: "en",
+	}
+
+	details, err := client.AlertDetails(ctx, "", request)
+
+	detailsErr := &AlertDetailsError{}
+	if !errors.As(err, &detailsErr) || len(detailsErr.Errors) != 2 {
+		t.Fatalf("expected every alert to fail, got: %v", err)
+	}
+
+	if !errors.Is(detailsErr.Errors["a"], context.Canceled) || len(details) != 0 {
+		t.Errorf("expected the context error, have: %v", detailsErr.Errors)
+	}
+
+	if have := alerts.requests(); have != 0 {
+		t.Errorf("want: %d requests, have: %d", 0, have)
+	}
+}
+
+func TestAlertDetailsEmpty(t *testing.T) {
+	details, err := (&Client{}).AlertDetails(context.TODO(), "", AlertDetailsRequest{Language: "en"})
+	if err != nil || len(details) != 0 {
+		t.Errorf("expected no details and no error, have: %v %v", details, err)
+	}
+}
+
+type mockAlertServer struct {
+	mu          sync.Mutex
+	paths       []string
+	inFlight    int
+	maxInFlight int
+}
+
+func (s *mockAlertServer) requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.paths)
+}
+
+// getMockAlertServer serves the weather alert fixture for every alert ID except "missing".
+// It records the requested paths and the highest number of concurrent requests.
+func getMockAlertServer(t *testing.T) (*httptest.Server, *mockAlertServer) {
+	data, err := ioutil.ReadFile("testdata/weather_alert.json")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	alerts := &mockAlertServer{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		alerts.mu.Lock()
+		alerts.paths = append(alerts.paths, r.URL.Path)
+		alerts.inFlight++
+		if alerts.inFlight > alerts.maxInFlight {
+			alerts.maxInFlight = alerts.inFlight
+		}
+		alerts.mu.Unlock()
+
+		defer func() {
+			alerts.mu.Lock()
+			alerts.inFlight--
+			alerts.mu.Unlock()
+		}()
+
+		time.Sleep(10 * time.Millisecond)
+
+		if path.Base(r.URL.Path) == "missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		_, _ = w.Write(data)
+	}))
+
+	return server, alerts
+}
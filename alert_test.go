@@ -1,6 +1,9 @@
 package weatherkit
 
 import (
+	"context"
+	"encoding/json"
+	"net/http"
 	"testing"
 )
 
@@ -10,10 +13,58 @@ func TestWeatherAlertRequestFullUrlGeneration(t *testing.T) {
 		Language: "en",
 	}
 
-	want := BaseUrl + "/api/v1/weatherAlert/en/test"
-	have := req.url()
+	want := DefaultBaseURL + "/api/v1/weatherAlert/en/test"
+	have := req.url(DefaultBaseURL)
 
 	if want != have {
 		t.Errorf("want: %s, have: %s", want, have)
 	}
 }
+
+func TestWeatherAlertResponse(t *testing.T) {
+	server, expected, err := getMockServerWithFileData("testdata/weather_alert.json", http.StatusOK)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer server.Close()
+
+	client := Client{BaseURL: server.URL}
+
+	response, err := client.Alert(context.TODO(), "", WeatherAlertRequest{ID: "f5d8d0a4-3a25-5b8e-a3b4-2a3a0f2f8c1e", Language: "en"})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	features := response.Area.Features
+	if len(features) != 2 {
+		t.Fatalf("want: %d features, have: %d", 2, len(features))
+	}
+
+	if features[0].Properties["areaId"] != "NYZ072" || features[1].Properties["areaName"] != "Islands" {
+		t.Errorf("expected the feature properties to be decoded, have: %v %v", features[0].Properties, features[1].Properties)
+	}
+
+	polygon := features[0].Geometry.Polygon
+	if features[0].Geometry.Type != GeometryTypePolygon || len(polygon) != 2 || len(polygon[1]) != 5 {
+		t.Errorf("expected a polygon with a hole, have: %+v", features[0].Geometry)
+	}
+
+	if have := polygon[0][2]; have.Longitude() != -73.93 || have.Latitude() != 40.80 {
+		t.Errorf("want: %v, have: %v", Position{-73.93, 40.80}, have)
+	}
+
+	if features[1].Geometry.Type != GeometryTypeMultiPolygon || len(features[1].Geometry.MultiPolygon) != 2 {
+		t.Errorf("expected a multi polygon, have: %+v", features[1].Geometry)
+	}
+
+	if have := len(response.Area.Polygons()); have != 3 {
+		t.Errorf("want: %d polygons, have: %d", 3, have)
+	}
+
+	actual, err := json.Marshal(response)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	assertJsonEqual(t, expected, actual)
+}
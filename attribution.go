@@ -6,8 +6,12 @@ type AttributionRequest struct {
 	Language string `json:"language,omitempty"`
 }
 
-func (o AttributionRequest) url() string {
-	return attribution(o.Language)
+func (o AttributionRequest) endpoint() Endpoint {
+	return EndpointAttribution
+}
+
+func (o AttributionRequest) url(base string) string {
+	return attribution(base, o.Language)
 }
 
 // AttributionResponse contains an official attribution branding.
@@ -9,8 +9,8 @@ func TestAttributionRequestFullUrlGeneration(t *testing.T) {
 		Language: "en",
 	}
 
-	want := BaseUrl + "/attribution/en"
-	have := req.url()
+	want := DefaultBaseURL + "/attribution/en"
+	have := req.url(DefaultBaseURL)
 
 	if want != have {
 		t.Errorf("want: %s, have: %s", want, have)
@@ -16,14 +16,18 @@ type AvailabilityRequest struct {
 	Country string
 }
 
-func (o AvailabilityRequest) url() string {
+func (o AvailabilityRequest) endpoint() Endpoint {
+	return EndpointAvailability
+}
+
+func (o AvailabilityRequest) url(base string) string {
 	q := url.Values{}
 
 	if o.Country != "" {
 		q.Add("country", o.Country)
 	}
 
-	return availabilityEndpoint(o.Latitude, o.Longitude, q)
+	return availabilityEndpoint(base, o.Latitude, o.Longitude, q)
 }
 
 // AvailabilityResponse has the data sets available for the specified location.
@@ -11,8 +11,8 @@ func TestAvailabilityRequestFullUrlGeneration(t *testing.T) {
 		Country:   "US",
 	}
 
-	want := BaseUrl + "/api/v1/availability/40.713/-74.006?country=US"
-	have := req.url()
+	want := DefaultBaseURL + "/api/v1/availability/40.713/-74.006?country=US"
+	have := req.url(DefaultBaseURL)
 
 	if want != have {
 		t.Errorf("want: %s, have: %s", want, have)
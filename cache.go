@@ -0,0 +1,242 @@
+package weatherkit
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// Cache stores encoded responses until they expire.
+// Implementations must be safe for concurrent use.
+type Cache interface {
+	// Get returns the value stored for key if it is present and has not expired.
+	Get(key string) ([]byte, bool)
+
+	// Set stores the value for key until the expiration time.
+	Set(key string, value []byte, expires time.Time)
+}
+
+// DefaultCacheTTL is how long responses without an expiration time are cached when Client.CacheTTL is not set.
+const DefaultCacheTTL = time.Hour
+
+// DefaultMemoryCacheCapacity is the number of entries held by a MemoryCache created with a capacity of zero.
+const DefaultMemoryCacheCapacity = 1024
+
+// NewMemoryCache creates an in-memory Cache holding up to capacity entries.
+// The least recently used entry is evicted once the capacity is reached.
+func NewMemoryCache(capacity int) *MemoryCache {
+	if capacity < 1 {
+		capacity = DefaultMemoryCacheCapacity
+	}
+
+	return &MemoryCache{
+		capacity: capacity,
+		entries:  map[string]*list.Element{},
+		order:    list.New(),
+	}
+}
+
+// MemoryCache is an in-memory least recently used Cache.
+// Construct with NewMemoryCache.
+type MemoryCache struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[string]*list.Element
+	order    *list.List
+}
+
+type memoryCacheEntry struct {
+	key     string
+	value   []byte
+	expires time.Time
+}
+
+// Get implements Cache.
+func (c *MemoryCache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := element.Value.(*memoryCacheEntry)
+	if !entry.expires.After(time.Now()) {
+		c.remove(element)
+		return nil, false
+	}
+
+	c.order.MoveToFront(element)
+
+	return entry.value, true
+}
+
+// Set implements Cache.
+func (c *MemoryCache) Set(key string, value []byte, expires time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		c.remove(element)
+	}
+
+	if !expires.After(time.Now()) {
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&memoryCacheEntry{
+		key:     key,
+		value:   value,
+		expires: expires,
+	})
+
+	for c.order.Len() > c.capacity {
+		c.remove(c.order.Back())
+	}
+}
+
+// Len returns the number of entries in the cache, including expired entries not yet evicted.
+func (c *MemoryCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+func (c *MemoryCache) remove(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.entries, element.Value.(*memoryCacheEntry).key)
+}
+
+// cachedWeather serves each requested data set from the Cache while it is fresh,
+// and requests only the missing data sets from the API.
+func (d *Client) cachedWeather(ctx context.Context, baseURL string, token string, request WeatherRequest) (*WeatherResponse, error) {
+	response := WeatherResponse{}
+	missing := DataSets{}
+
+	for _, dataSet := range request.DataSets {
+		value, ok := d.Cache.Get(weatherCacheKey(baseURL, request, dataSet))
+		hit := ok && response.setDataSet(dataSet, value) == nil
+		d.observeCache(EndpointWeather, hit)
+
+		if !hit {
+			missing = append(missing, dataSet)
+		}
+	}
+
+	if len(missing) < 1 {
+		return &response, nil
+	}
+
+	partial := request
+	partial.DataSets = missing
+
+	fetched := WeatherResponse{}
+	err := d.fetchWeather(ctx, baseURL, token, partial, &fetched)
+	if err != nil {
+		return &fetched, err
+	}
+
+	now := time.Now()
+	for _, dataSet := range missing {
+		block, expires := fetched.dataSet(dataSet)
+		if block == nil {
+			continue
+		}
+
+		value, err := json.Marshal(block)
+		if err != nil {
+			return &fetched, err
+		}
+
+		err = response.setDataSet(dataSet, value)
+		if err != nil {
+			return &fetched, err
+		}
+
+		if expires != nil && expires.After(now) {
+			d.Cache.Set(weatherCacheKey(baseURL, request, dataSet), value, *expires)
+		}
+	}
+
+	return &response, nil
+}
+
+// cachedGet serves a response other than weather, such as availability or attribution, from cache.
+// Responses are stored until the time returned by expires for the decoded output.
+func (d *Client) cachedGet(ctx context.Context, cache Cache, baseURL string, token string, request urlBuilder, output interface{}, expires func(now time.Time) time.Time) error {
+	key := request.url(baseURL)
+
+	if value, ok := cache.Get(key); ok && json.Unmarshal(value, output) == nil {
+		d.observeCache(request.endpoint(), true)
+		return nil
+	}
+
+	d.observeCache(request.endpoint(), false)
+
+	err := d.get(ctx, baseURL, token, request, output)
+	if err != nil {
+		return err
+	}
+
+	value, err := json.Marshal(output)
+	if err != nil {
+		return err
+	}
+
+	cache.Set(key, value, expires(time.Now()))
+
+	return nil
+}
+
+// ttlExpiry stores responses without an expiration time for the CacheTTL of the Client.
+func (d *Client) ttlExpiry(now time.Time) time.Time {
+	return now.Add(d.cacheTTL())
+}
+
+// alertExpiry stores alert details until the alert expires, or for the CacheTTL of the Client when it
+// carries no future expiration time. Details do not change once an alert is issued, so they are cached
+// by alert ID and language.
+func (d *Client) alertExpiry(response *WeatherAlertResponse) func(now time.Time) time.Time {
+	return func(now time.Time) time.Time {
+		if response.ExpireTime != nil && response.ExpireTime.After(now) {
+			return *response.ExpireTime
+		}
+
+		return d.ttlExpiry(now)
+	}
+}
+
+func (d *Client) cacheTTL() time.Duration {
+	if d.CacheTTL > 0 {
+		return d.CacheTTL
+	}
+
+	return DefaultCacheTTL
+}
+
+// weatherCacheKey identifies a single data set of a weather request.
+// Times are normalized to UTC so equivalent requests share a key.
+func weatherCacheKey(baseURL string, request WeatherRequest, dataSet DataSet) string {
+	normalized := request
+	normalized.DataSets = DataSets{dataSet}
+	normalized.CurrentAsOf = utc(request.CurrentAsOf)
+	normalized.DailyStart = utc(request.DailyStart)
+	normalized.DailyEnd = utc(request.DailyEnd)
+	normalized.HourlyStart = utc(request.HourlyStart)
+	normalized.HourlyEnd = utc(request.HourlyEnd)
+
+	return normalized.url(baseURL)
+}
+
+func utc(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+
+	u := t.UTC()
+	return &u
+}
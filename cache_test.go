@@ -0,0 +1,242 @@
+package weatherkit
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMemoryCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := NewMemoryCache(2)
+	expires := time.Now().Add(time.Hour)
+
+	cache.Set("a", []byte("a"), expires)
+	cache.Set("b", []byte("b"), expires)
+
+	_, ok := cache.Get("a")
+	if !ok {
+		t.Fatal("expected a to be cached")
+	}
+
+	cache.Set("c", []byte("c"), expires)
+
+	if _, ok := cache.Get("b"); ok {
+		t.Errorf("expected b to be evicted")
+	}
+
+	for _, key := range []string{"a", "c"} {
+		value, ok := cache.Get(key)
+		if !ok || string(value) != key {
+			t.Errorf("expected %s to be cached, have: %s", key, value)
+		}
+	}
+}
+
+func TestMemoryCacheExpires(t *testing.T) {
+	cache := NewMemoryCache(0)
+
+	cache.Set("expired", []byte("x"), time.Now().Add(-time.Second))
+	if _, ok := cache.Get("expired"); ok {
+		t.Errorf("expected expired entry not to be returned")
+	}
+
+	cache.Set("short", []byte("x"), time.Now().Add(10*time.Millisecond))
+	time.Sleep(20 * time.Millisecond)
+
+	if _, ok := cache.Get("short"); ok {
+		t.Errorf("expected entry to expire")
+	}
+
+	if cache.Len() != 0 {
+		t.Errorf("want: %d entries, have: %d", 0, cache.Len())
+	}
+}
+
+func TestWeatherCacheReusesFreshDataSets(t *testing.T) {
+	server, requested := getMockDataSetServer(t, time.Now().Add(time.Hour))
+	defer server.Close()
+
+	client := Client{BaseURL: server.URL, Cache: NewMemoryCache(0)}
+
+	request := WeatherRequest{
+		Language:  "en",
+		Latitude:  40.713,
+		Longitude: -74.006,
+		DataSets:  DataSets{DataSetForecastDaily, DataSetForecastHourly},
+	}
+
+	first, err := client.Weather(context.TODO(), "", request)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	second, err := client.Weather(context.TODO(), "", request)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	expected, _ := json.Marshal(first)
+	actual, _ := json.Marshal(second)
+	assertJsonEqual(t, expected, actual)
+
+	request.DataSets = DataSets{DataSetForecastHourly, DataSetForecastNextHour}
+
+	third, err := client.Weather(context.TODO(), "", request)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if third.ForcastHourly == nil || third.ForcastNextHour == nil {
+		t.Errorf("expected hourly and next hour forecasts, have: %+v", third)
+	}
+
+	if third.ForcastDaily != nil {
+		t.Errorf("expected daily forecast to be excluded")
+	}
+
+	want := []string{"forecastDaily,forecastHourly", "forecastNextHour"}
+	if strings.Join(*requested, "|") != strings.Join(want, "|") {
+		t.Errorf("want: %v, have: %v", want, *requested)
+	}
+}
+
+func TestWeatherCacheSkipsExpiredDataSets(t *testing.T) {
+	server, requested := getMockDataSetServer(t, time.Now().Add(-time.Minute))
+	defer server.Close()
+
+	client := Client{BaseURL: server.URL, Cache: NewMemoryCache(0)}
+
+	request := WeatherRequest{
+		Language: "en",
+		DataSets: DataSets{DataSetForecastDaily},
+	}
+
+	for i := 0; i < 2; i++ {
+		_, err := client.Weather(context.TODO(), "", request)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+
+	if len(*requested) != 2 {
+		t.Errorf("want: %d requests, have: %d", 2, len(*requested))
+	}
+}
+
+func TestWeatherCacheKeyNormalizesTimes(t *testing.T) {
+	ts := time.Date(2022, 7, 10, 6, 14, 11, 0, time.UTC)
+	local := ts.In(time.FixedZone("EDT", -4*60*60))
+
+	a := weatherCacheKey(DefaultBaseURL, WeatherRequest{HourlyStart: &ts}, DataSetForecastHourly)
+	b := weatherCacheKey(DefaultBaseURL, WeatherRequest{HourlyStart: &local}, DataSetForecastHourly)
+
+	if a != b {
+		t.Errorf("expected equal keys, have: %s and %s", a, b)
+	}
+}
+
+// getMockDataSetServer serves the requested data sets from the full weather fixture with the given expiration time.
+// It records the dataSets parameter of every request it receives.
+func getMockDataSetServer(t *testing.T, expires time.Time) (*httptest.Server, *[]string) {
+	bytes, err := ioutil.ReadFile("testdata/full_weather.json")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	full := map[string]map[string]interface{}{}
+	err = json.Unmarshal(bytes, &full)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	for _, block := range full {
+		block["metadata"].(map[string]interface{})["expireTime"] = expires.UTC().Format(time.RFC3339)
+	}
+
+	mu := sync.Mutex{}
+	requested := []string{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		dataSets := r.URL.Query().Get("dataSets")
+
+		mu.Lock()
+		requested = append(requested, dataSets)
+		mu.Unlock()
+
+		response := map[string]interface{}{}
+		for _, dataSet := range strings.Split(dataSets, ",") {
+			if block, ok := full[dataSet]; ok {
+				response[dataSet] = block
+			}
+		}
+
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+
+	return server, &requested
+}
+
+func TestAlertNotCached(t *testing.T) {
+	server, alerts := getMockAlertServer(t)
+	defer server.Close()
+
+	client := Client{BaseURL: server.URL, Cache: NewMemoryCache(0)}
+	request := WeatherAlertRequest{ID: "a", Language: "en"}
+
+	for i := 0; i < 2; i++ {
+		_, err := client.Alert(context.TODO(), "", request)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+
+	if have := alerts.requests(); have != 2 {
+		t.Errorf("expected Alert to ignore the Client Cache, want: %d requests, have: %d", 2, have)
+	}
+
+	_, err := client.AlertDetails(context.TODO(), "", AlertDetailsRequest{
+		Alerts:   &WeatherAlertCollection{Alerts: []WeatherAlertSummary{{ID: "a"}}},
+		Language: "en",
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if have := alerts.requests(); have != 3 {
+		t.Errorf("expected AlertDetails without a Cache to request the details, want: %d requests, have: %d", 3, have)
+	}
+}
+
+func TestAlertExpiry(t *testing.T) {
+	now := time.Now()
+	future := now.Add(time.Minute)
+	past := now.Add(-time.Minute)
+
+	client := Client{CacheTTL: time.Hour}
+
+	tests := map[string]struct {
+		expireTime *time.Time
+		want       time.Time
+	}{
+		"future": {&future, future},
+		"past":   {&past, now.Add(time.Hour)},
+		"none":   {nil, now.Add(time.Hour)},
+	}
+
+	for name, test := range tests {
+		response := WeatherAlertResponse{}
+		response.ExpireTime = test.expireTime
+
+		have := client.alertExpiry(&response)(now)
+		if !have.Equal(test.want) {
+			t.Errorf("%s: want: %s, have: %s", name, test.want, have)
+		}
+	}
+}
@@ -1,9 +1,12 @@
 package weatherkit
 
 import (
+	"bytes"
 	"compress/gzip"
 	"context"
+	"crypto"
 	"encoding/json"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
@@ -14,22 +17,23 @@ import (
 // DefaultUserAgent to send along with requests.
 const DefaultUserAgent = "shawntoffel/go-weatherkit"
 
+// DefaultMaxResponseSize is the maximum decompressed response size when Client.MaxResponseSize is not set.
+const DefaultMaxResponseSize = 32 << 20
+
 // NewCredentialedClient creates a new client with creds.
+// The private key is parsed once rather than for every token, and a key which fails to parse is reported by the first request.
+// A client configured with WithTokenRefresh starts refreshing tokens in the background here.
 func NewCredentialedClient(credentials Credentials, opts ...CredentialedClientOption) *CredentialedClient {
-	cc := &CredentialedClient{
+	client := &CredentialedClient{
 		credentials: credentials,
-		options:     defaultcredentialedClientOptions(),
-	}
-
-	if opts == nil || len(opts) < 1 {
-		return cc
+		options:     newCredentialedClientOptions(opts),
 	}
 
-	for _, opt := range opts {
-		opt.apply(cc.options)
+	if client.options.refresh {
+		client.tokenSource()
 	}
 
-	return cc
+	return client
 }
 
 // CredentialedClient is a WeatherKit API client.
@@ -37,70 +41,151 @@ func NewCredentialedClient(credentials Credentials, opts ...CredentialedClientOp
 type CredentialedClient struct {
 	options     *credentialedClientOptions
 	credentials Credentials
-	mu          sync.Mutex
-	token       string
-	exp         time.Time
+	once        sync.Once
+	tokens      TokenSource
+	refresher   *tokenRefresher
 }
 
-func (c *CredentialedClient) getToken() (string, error) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
+// Close stops the background token refresh configured with WithTokenRefresh.
+// Requests made after Close obtain tokens synchronously. It is safe to call Close more than once.
+func (c *CredentialedClient) Close() error {
+	c.tokenSource()
 
-	if c.options == nil {
-		c.options = defaultcredentialedClientOptions()
+	if c.refresher != nil {
+		c.refresher.Close()
 	}
 
-	if c.options.disableCache {
-		signed, _, err := c.credentials.SignedJWT(c.options.tokenDuration)
-		return signed, err
+	return nil
+}
+
+func (c *CredentialedClient) getToken(ctx context.Context) (string, error) {
+	token, _, err := c.tokenSource().Token(ctx)
+	if err != nil {
+		return "", &TokenError{Err: err}
 	}
 
-	// Use a minute buffer to allow for req/resp time.
-	if len(c.token) > 0 && c.exp.After(time.Now().UTC().Add(time.Minute)) {
-		return c.token, nil
+	return token, nil
+}
+
+// tokenSource returns the configured TokenSource, or caches tokens signed with the credentials.
+// Either is refreshed in the background when WithTokenRefresh is set.
+func (c *CredentialedClient) tokenSource() TokenSource {
+	c.once.Do(func() {
+		if c.options == nil {
+			c.options = newCredentialedClientOptions(nil)
+		}
+
+		c.tokens = c.options.tokenSource
+		if c.tokens == nil {
+			signer := c.newSigner()
+			c.tokens = tokenSourceFunc(func(ctx context.Context) (string, time.Time, error) {
+				return c.signToken(ctx, signer)
+			})
+		}
+
+		switch {
+		case c.options.refresh:
+			c.refresher = newTokenRefresher(c.tokens, c.options.refreshFraction, c.options.refreshErrorHandler)
+			c.refresher.start()
+			c.tokens = c.refresher
+		case c.options.tokenSource == nil && !c.options.disableCache:
+			c.tokens = NewCachingTokenSource(c.tokens)
+		}
+	})
+
+	return c.tokens
+}
+
+// newSigner returns the TokenSource signing tokens with the credentials, or with the signer configured by WithSigner.
+func (c *CredentialedClient) newSigner() TokenSource {
+	if c.options.signer == nil {
+		return NewSigningTokenSource(c.credentials, c.options.tokenDuration)
 	}
 
-	signed, exp, err := c.credentials.SignedJWT(c.options.tokenDuration)
+	signer, err := NewSignerTokenSource(c.credentials, c.options.signer, c.options.tokenDuration)
 	if err != nil {
-		return "", err
+		return tokenSourceFunc(func(ctx context.Context) (string, time.Time, error) {
+			return "", time.Time{}, err
+		})
 	}
 
-	c.token = signed
-	c.exp = exp
+	return signer
+}
+
+func (c *CredentialedClient) signToken(ctx context.Context, signer TokenSource) (string, time.Time, error) {
+	ctx, span := startSpan(c.tracer(), ctx, SpanSignToken)
+	defer span.End()
 
-	return c.token, nil
+	signed, exp, err := signer.Token(ctx)
+	c.observeSigning(err)
+	if err != nil {
+		span.RecordError(err)
+		c.logError(ctx, "failed to sign developer token", "key_id", c.credentials.KeyID, "error", err)
+		return "", time.Time{}, err
+	}
+
+	c.logDebug(ctx, "signed developer token", "key_id", c.credentials.KeyID, "expires", exp)
+
+	return signed, exp, nil
 }
 
 // Weather obtains weather data for the specified location.
 func (d *CredentialedClient) Weather(ctx context.Context, request WeatherRequest) (*WeatherResponse, error) {
-	token, err := d.getToken()
+	ctx, span := d.startRequestSpan(ctx)
+	defer span.End()
+
+	token, err := d.getToken(ctx)
 	if err != nil {
+		span.RecordError(err)
 		return nil, err
 	}
-	return d.options.client.Weather(ctx, token, request)
+	return d.options.client.weather(ctx, d.baseURL(), token, request)
 }
 
 // Availability determines the data sets available for the specified location.
 func (d *CredentialedClient) Availability(ctx context.Context, request AvailabilityRequest) (*AvailabilityResponse, error) {
-	token, err := d.getToken()
+	ctx, span := d.startRequestSpan(ctx)
+	defer span.End()
+
+	token, err := d.getToken(ctx)
 	if err != nil {
+		span.RecordError(err)
 		return nil, err
 	}
-	return d.options.client.Availability(ctx, token, request)
+	return d.options.client.availability(ctx, d.baseURL(), token, request)
 }
 
 // Alert receives information on an active weather alert.
 func (d *CredentialedClient) Alert(ctx context.Context, request WeatherAlertRequest) (*WeatherAlertResponse, error) {
-	token, err := d.getToken()
+	ctx, span := d.startRequestSpan(ctx)
+	defer span.End()
+
+	token, err := d.getToken(ctx)
 	if err != nil {
+		span.RecordError(err)
 		return nil, err
 	}
-	return d.options.client.Alert(ctx, token, request)
+	return d.options.client.alert(ctx, d.baseURL(), token, request)
 }
 
 // Attribution retrieves official attribution branding.
 func (d *CredentialedClient) Attribution(ctx context.Context, request AttributionRequest) (*AttributionResponse, error) {
-	return d.options.client.Attribution(ctx, request)
+	return d.options.client.attribution(ctx, d.baseURL(), request)
+}
+
+// startRequestSpan starts the request span of an API call before its token is obtained,
+// so token signing is traced within it. The Client annotates the span once it sends the request.
+func (d *CredentialedClient) startRequestSpan(ctx context.Context) (context.Context, Span) {
+	ctx, span := startSpan(d.options.client.Tracer, ctx, SpanRequest)
+	return withRequestSpan(ctx, span), span
+}
+
+func (d *CredentialedClient) baseURL() string {
+	if len(d.options.baseURL) > 0 {
+		return normalizeBaseURL(d.options.baseURL)
+	}
+
+	return d.options.client.baseURL()
 }
 
 // CredentialedClientOption configures a CredentialedClient.
@@ -109,9 +194,18 @@ type CredentialedClientOption interface {
 }
 
 type credentialedClientOptions struct {
-	disableCache  bool
-	client        *Client
-	tokenDuration time.Duration
+	disableCache        bool
+	tokenSource         TokenSource
+	signer              crypto.Signer
+	refresh             bool
+	refreshFraction     float64
+	refreshErrorHandler func(error)
+	client              *Client
+	tokenDuration       time.Duration
+	baseURL             string
+	logger              Logger
+	metrics             Metrics
+	tracer              Tracer
 }
 
 type funcOption struct {
@@ -130,11 +224,28 @@ func newFuncOption(f func(*credentialedClientOptions)) *funcOption {
 
 func defaultcredentialedClientOptions() *credentialedClientOptions {
 	return &credentialedClientOptions{
-		client:        &Client{},
 		tokenDuration: defaultTokenDuration,
 	}
 }
 
+func newCredentialedClientOptions(opts []CredentialedClientOption) *credentialedClientOptions {
+	options := defaultcredentialedClientOptions()
+
+	for _, opt := range opts {
+		opt.apply(options)
+	}
+
+	if options.client == nil {
+		options.client = &Client{
+			Logger:  options.logger,
+			Metrics: options.metrics,
+			Tracer:  options.tracer,
+		}
+	}
+
+	return options
+}
+
 // WithoutCache returns an Option which disables token caching.
 // A new JWT will be generated for each request.
 func WithoutCache() CredentialedClientOption {
@@ -143,6 +254,44 @@ func WithoutCache() CredentialedClientOption {
 	})
 }
 
+// WithTokenSource returns an Option which obtains developer tokens from source instead of signing
+// them with the credentials of the client. The source is called for every request, so wrap it with
+// NewCachingTokenSource to reuse tokens until they expire.
+func WithTokenSource(source TokenSource) CredentialedClientOption {
+	return newFuncOption(func(o *credentialedClientOptions) {
+		o.tokenSource = source
+	})
+}
+
+// WithSigner returns an Option which signs developer tokens with signer instead of the PrivateKey of the
+// credentials, such as a key held by an HSM or a cloud KMS. See NewSignerTokenSource for its requirements.
+// An unsuitable signer is reported by the first request.
+func WithSigner(signer crypto.Signer) CredentialedClientOption {
+	return newFuncOption(func(o *credentialedClientOptions) {
+		o.signer = signer
+	})
+}
+
+// WithTokenRefresh returns an Option which renews developer tokens in the background after fraction of
+// their lifetime, so requests do not wait for tokens to be signed. For example, a fraction of 0.5 renews
+// a 10 minute token after 5 minutes. Fractions which are not between 0 and 1 use DefaultTokenRefreshFraction.
+// Requests fall back to obtaining a token themselves when no valid token is available.
+// Stop the refresh with CredentialedClient.Close.
+func WithTokenRefresh(fraction float64) CredentialedClientOption {
+	return newFuncOption(func(o *credentialedClientOptions) {
+		o.refresh = true
+		o.refreshFraction = fraction
+	})
+}
+
+// WithTokenRefreshErrorHandler returns an Option which configures a function called with the errors of
+// background token refreshes configured with WithTokenRefresh. Failed refreshes are retried after 10 seconds.
+func WithTokenRefreshErrorHandler(handler func(error)) CredentialedClientOption {
+	return newFuncOption(func(o *credentialedClientOptions) {
+		o.refreshErrorHandler = handler
+	})
+}
+
 // WithClient returns an Option which configures a custom Client.
 func WithClient(client *Client) CredentialedClientOption {
 	return newFuncOption(func(o *credentialedClientOptions) {
@@ -158,6 +307,38 @@ func WithTokenDuration(duration time.Duration) CredentialedClientOption {
 	})
 }
 
+// WithBaseURL returns an Option which configures the WeatherKit API base URL used by this client.
+// It takes precedence over the BaseURL of the underlying Client.
+func WithBaseURL(baseURL string) CredentialedClientOption {
+	return newFuncOption(func(o *credentialedClientOptions) {
+		o.baseURL = baseURL
+	})
+}
+
+// WithLogger returns an Option which configures a Logger for token signing events.
+// Unless a Client is configured with WithClient, requests are logged to it as well.
+func WithLogger(logger Logger) CredentialedClientOption {
+	return newFuncOption(func(o *credentialedClientOptions) {
+		o.logger = logger
+	})
+}
+
+// WithMetrics returns an Option which configures Metrics for token signing measurements.
+// Unless a Client is configured with WithClient, requests are measured by it as well.
+func WithMetrics(metrics Metrics) CredentialedClientOption {
+	return newFuncOption(func(o *credentialedClientOptions) {
+		o.metrics = metrics
+	})
+}
+
+// WithTracer returns an Option which configures a Tracer for token signing spans.
+// Unless a Client is configured with WithClient, requests are traced by it as well.
+func WithTracer(tracer Tracer) CredentialedClientOption {
+	return newFuncOption(func(o *credentialedClientOptions) {
+		o.tracer = tracer
+	})
+}
+
 // Client is a WeatherKit API client without Credentials.
 // Use NewCredentialedClient for automatic JWT handling.
 type Client struct {
@@ -165,70 +346,315 @@ type Client struct {
 
 	// The UserAgent header value to send along with requests.
 	UserAgent string
+
+	// The WeatherKit API base URL. Defaults to DefaultBaseURL.
+	BaseURL string
+
+	// RetryPolicy decides whether unsuccessful requests are attempted again.
+	// Requests are not retried when nil.
+	RetryPolicy RetryPolicy
+
+	// RateLimiter is waited on before every request, including retries.
+	RateLimiter RateLimiter
+
+	// Quota records every request sent and refuses new ones once its budget is exhausted.
+	Quota *Quota
+
+	// Cache serves each requested weather data set until its metadata expiration time.
+	// Only the data sets missing from the Cache are requested from the API.
+	// Availability and attribution responses are cached for CacheTTL.
+	Cache Cache
+
+	// The time to cache responses which carry no expiration time. Defaults to DefaultCacheTTL.
+	CacheTTL time.Duration
+
+	// Coalesce shares a single request between concurrent Weather calls for the same URL.
+	// Every caller receives its own copy of the response, made with the token of the first caller.
+	// A caller whose context is done stops waiting without canceling the request for the others.
+	Coalesce bool
+
+	// Middleware wraps every API call, with the first Middleware being the outermost.
+	Middleware []Middleware
+
+	// Logger receives structured events for every request. Nothing is logged when nil.
+	// Authorization headers are redacted.
+	Logger Logger
+
+	// Metrics receives measurements of requests, retries and cache usage. Nothing is measured when nil.
+	Metrics Metrics
+
+	// Tracer starts a span around every request, with nested spans for each attempt. Nothing is traced when nil.
+	Tracer Tracer
+
+	// PropagateTraceContext sends the W3C traceparent header of the attempt span along with requests.
+	PropagateTraceContext bool
+
+	// Strict reports the JSON fields of every response which are not modeled by the response types,
+	// to notice changes of the API schema. They are logged as a warning to the Logger and recorded in
+	// the RawResponse of CaptureRawResponse. Without either, Strict has no effect. See UnknownFields.
+	Strict bool
+
+	// The maximum size in bytes of a decompressed response body. Larger responses fail to decode
+	// with ErrResponseTooLarge. Defaults to DefaultMaxResponseSize, a negative value disables the limit.
+	MaxResponseSize int64
+
+	flights coalescer
 }
 
 // Weather obtains weather data for the specified location.
 // The token parameter is a JWT developer token.
 func (d *Client) Weather(ctx context.Context, token string, request WeatherRequest) (*WeatherResponse, error) {
-	response := WeatherResponse{}
-	err := d.get(ctx, token, request, &response)
-	return &response, err
+	return d.weather(ctx, d.baseURL(), token, request)
 }
 
 // Availability determines the data sets available for the specified location.
 // The token parameter is a JWT developer token.
 func (d *Client) Availability(ctx context.Context, token string, request AvailabilityRequest) (*AvailabilityResponse, error) {
-	response := AvailabilityResponse{}
-	err := d.get(ctx, token, request, &response)
-	return &response, err
+	return d.availability(ctx, d.baseURL(), token, request)
 }
 
 // Alert receives information on an active weather alert.
 // The token parameter is a JWT developer token.
 func (d *Client) Alert(ctx context.Context, token string, request WeatherAlertRequest) (*WeatherAlertResponse, error) {
-	response := WeatherAlertResponse{}
-	err := d.get(ctx, token, request, &response)
-	return &response, err
+	return d.alert(ctx, d.baseURL(), token, request)
 }
 
 // Attribution retrieves official attribution branding.
 func (d *Client) Attribution(ctx context.Context, request AttributionRequest) (*AttributionResponse, error) {
-	response := AttributionResponse{}
-	err := d.get(ctx, "", request, &response)
+	return d.attribution(ctx, d.baseURL(), request)
+}
+
+func (d *Client) weather(ctx context.Context, baseURL string, token string, request WeatherRequest) (*WeatherResponse, error) {
+	if d.Cache != nil && len(request.DataSets) > 0 {
+		return d.cachedWeather(ctx, baseURL, token, request)
+	}
+
+	response := WeatherResponse{}
+	err := d.fetchWeather(ctx, baseURL, token, request, &response)
+	return &response, err
+}
+
+// fetchWeather requests weather data from the API, sharing identical in-flight requests when Coalesce is set.
+func (d *Client) fetchWeather(ctx context.Context, baseURL string, token string, request WeatherRequest, response *WeatherResponse) error {
+	if !d.Coalesce {
+		return d.get(ctx, baseURL, token, request, response)
+	}
+
+	// The raw response is recorded by the flight and copied to every waiter which captures it.
+	output := coalescedWeather{Response: response, Raw: rawResponseFrom(ctx)}
+
+	return d.flights.do(ctx, request.url(baseURL), &output, func(ctx context.Context) (interface{}, error) {
+		// The flight may outlive the caller which started it, so it traces a request span of its own.
+		ctx = withRequestSpan(ctx, nil)
+
+		shared := coalescedWeather{Response: &WeatherResponse{}, Raw: &RawResponse{}}
+		err := d.get(CaptureRawResponse(ctx, shared.Raw), baseURL, token, request, shared.Response)
+		return &shared, err
+	})
+}
+
+type coalescedWeather struct {
+	Response *WeatherResponse
+	Raw      *RawResponse
+}
+
+func (d *Client) availability(ctx context.Context, baseURL string, token string, request AvailabilityRequest) (*AvailabilityResponse, error) {
+	response := AvailabilityResponse{}
+	if d.Cache != nil {
+		err := d.cachedGet(ctx, d.Cache, baseURL, token, request, &response, d.ttlExpiry)
+		return &response, err
+	}
+
+	err := d.get(ctx, baseURL, token, request, &response)
 	return &response, err
 }
 
-func (d *Client) get(ctx context.Context, token string, request urlBuilder, output interface{}) error {
-	if d.HttpClient == nil {
-		d.HttpClient = &http.Client{}
+func (d *Client) alert(ctx context.Context, baseURL string, token string, request WeatherAlertRequest) (*WeatherAlertResponse, error) {
+	response := WeatherAlertResponse{}
+	err := d.get(ctx, baseURL, token, request, &response)
+	return &response, err
+}
+
+func (d *Client) attribution(ctx context.Context, baseURL string, request AttributionRequest) (*AttributionResponse, error) {
+	response := AttributionResponse{}
+	if d.Cache != nil {
+		err := d.cachedGet(ctx, d.Cache, baseURL, "", request, &response, d.ttlExpiry)
+		return &response, err
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, request.url(), nil)
+	err := d.get(ctx, baseURL, "", request, &response)
+	return &response, err
+}
+
+func (d *Client) get(ctx context.Context, baseURL string, token string, request urlBuilder, output interface{}) error {
+	ctx, span, end := startRequestSpan(d.Tracer, ctx)
+	defer end()
+
+	req, err := d.newRequest(ctx, request.url(baseURL), token)
 	if err != nil {
+		span.RecordError(err)
 		return err
 	}
 
-	req.Header.Add("User-Agent", d.userAgent())
-	req.Header.Add("Accept", "application/json; charset=utf-8")
-	req.Header.Add("Accept-Encoding", "gzip")
-
-	if len(token) > 0 {
-		req.Header.Add("Authorization", "Bearer "+token)
+	call := &Call{
+		Request:     request,
+		Endpoint:    request.endpoint(),
+		HTTPRequest: req,
+		Result:      output,
 	}
 
-	response, err := d.HttpClient.Do(req)
+	setRequestAttributes(span, call)
+
+	err = chain(d.Middleware, d.handle)(ctx, call)
+	setResponseAttributes(span, call, err)
+
+	return err
+}
+
+// handle is the innermost Handler of the Middleware chain.
+func (d *Client) handle(ctx context.Context, call *Call) error {
+	start := time.Now()
+	d.logDebug(ctx, "sending request", "endpoint", call.Endpoint, "url", call.HTTPRequest.URL.String(), "headers", redactHeaders(call.HTTPRequest.Header))
+
+	err := d.send(ctx, call)
+	duration := time.Since(start)
+
+	d.observeRequest(call, duration)
+	d.logResult(ctx, call, duration, err)
+
+	return err
+}
+
+func (d *Client) send(ctx context.Context, call *Call) error {
+	response, err := d.do(ctx, call.Endpoint, call.HTTPRequest.WithContext(ctx))
 	if err != nil {
 		return err
 	}
 
 	defer response.Body.Close()
 
+	response.Body = &countingReadCloser{ReadCloser: response.Body}
+	call.HTTPResponse = response
+
+	raw := recordRawResponse(ctx, response)
+
 	err = validateResponse(response)
 	if err != nil {
+		if raw != nil {
+			raw.Body = err.(*RestError).Body
+		}
+
 		return err
 	}
 
-	return decode(response, &output)
+	var body *bytes.Buffer
+	if raw != nil {
+		body = &bytes.Buffer{}
+		defer func() { raw.Body = body.Bytes() }()
+	}
+
+	err = decode(response, call.Result, d.maxResponseSize(), body)
+	if err != nil {
+		d.incCounter(MetricDecodeFailures, Labels{"endpoint": string(call.Endpoint)})
+		return &DecodeError{Endpoint: call.Endpoint, Err: err}
+	}
+
+	if d.Strict {
+		d.reportUnknownFields(ctx, call, raw)
+	}
+
+	return nil
+}
+
+// do sends the request, retrying unsuccessful attempts as directed by the RetryPolicy.
+func (d *Client) do(ctx context.Context, endpoint Endpoint, req *http.Request) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		err := d.acquire(ctx, endpoint)
+		if err != nil {
+			return nil, err
+		}
+
+		response, err := d.attempt(ctx, req, attempt)
+		if d.RetryPolicy == nil || (err == nil && response.StatusCode == http.StatusOK) {
+			return response, err
+		}
+
+		delay, retry := d.RetryPolicy.Retry(attempt, response, err)
+		if !retry || !fitsDeadline(ctx, delay) {
+			return response, err
+		}
+
+		d.incCounter(MetricRetries, Labels{"endpoint": string(endpoint)})
+		d.logRetry(ctx, endpoint, attempt, delay, response, err)
+
+		if response != nil {
+			discard(response)
+		}
+
+		if err := sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// attempt sends the request once, within its own span.
+func (d *Client) attempt(ctx context.Context, req *http.Request, attempt int) (*http.Response, error) {
+	if d.Tracer == nil {
+		return d.httpClient().Do(req)
+	}
+
+	ctx, span := d.Tracer.Start(ctx, SpanAttempt)
+	defer span.End()
+
+	span.SetAttribute("attempt", attempt)
+
+	req = req.Clone(ctx)
+	if traceParent := span.TraceParent(); d.PropagateTraceContext && len(traceParent) > 0 {
+		req.Header.Set("traceparent", traceParent)
+	}
+
+	response, err := d.httpClient().Do(req)
+	if err != nil {
+		span.RecordError(err)
+		return response, err
+	}
+
+	span.SetAttribute("status", response.StatusCode)
+
+	return response, nil
+}
+
+// acquire waits for the RateLimiter and reserves a request from the Quota.
+func (d *Client) acquire(ctx context.Context, endpoint Endpoint) error {
+	if d.RateLimiter != nil {
+		err := d.RateLimiter.Wait(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	if d.Quota != nil {
+		return d.Quota.reserve(endpoint)
+	}
+
+	return nil
+}
+
+func (d *Client) newRequest(ctx context.Context, url string, token string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("User-Agent", d.userAgent())
+	req.Header.Add("Accept", "application/json; charset=utf-8")
+	req.Header.Add("Accept-Encoding", "gzip")
+
+	if len(token) > 0 {
+		req.Header.Add("Authorization", "Bearer "+token)
+	}
+
+	return req, nil
 }
 
 func (d *Client) userAgent() string {
@@ -239,31 +665,91 @@ func (d *Client) userAgent() string {
 	return DefaultUserAgent
 }
 
+func (d *Client) httpClient() *http.Client {
+	if d.HttpClient != nil {
+		return d.HttpClient
+	}
+
+	return http.DefaultClient
+}
+
+func (d *Client) baseURL() string {
+	if len(d.BaseURL) > 0 {
+		return normalizeBaseURL(d.BaseURL)
+	}
+
+	return DefaultBaseURL
+}
+
 func validateResponse(response *http.Response) error {
 	if response.StatusCode == http.StatusOK {
 		return nil
 	}
 
+	restError := &RestError{
+		Response: response,
+		Body:     readErrorBody(response),
+	}
+
 	errorResponse := ErrorResponse{}
+	if json.Unmarshal(restError.Body, &errorResponse) == nil {
+		restError.ErrorResponse = &errorResponse
+	}
+
+	return restError
+}
+
+// readErrorBody reads up to MaxErrorBodySize bytes of the decompressed body.
+// If none of the body can be decompressed, the raw bytes are returned instead.
+func readErrorBody(response *http.Response) []byte {
+	raw, _ := ioutil.ReadAll(io.LimitReader(response.Body, MaxErrorBodySize))
+
+	if len(response.Header.Get("Content-Encoding")) < 1 {
+		return raw
+	}
 
-	err := decode(response, &errorResponse)
+	reader, err := gzip.NewReader(bytes.NewReader(raw))
 	if err != nil {
-		return err
+		return raw
 	}
 
-	return &RestError{
-		Response:      response,
-		ErrorResponse: &errorResponse,
+	body, err := ioutil.ReadAll(io.LimitReader(reader, MaxErrorBodySize))
+	if err != nil && len(body) < 1 {
+		return raw
 	}
+
+	return body
 }
 
-func decode(response *http.Response, into interface{}) error {
+// decode streams the JSON body into into, copying the decompressed body into raw if it is not nil.
+func decode(response *http.Response, into interface{}, maxSize int64, raw *bytes.Buffer) error {
 	body, err := decompress(response)
 	if err != nil {
 		return err
 	}
 
-	return unmarshal(body, into)
+	if reader, ok := body.(*gzip.Reader); ok {
+		defer gzipReaders.Put(reader)
+	}
+
+	if maxSize > 0 {
+		body = &limitedReader{reader: body, remaining: maxSize, max: maxSize}
+	}
+
+	if raw == nil {
+		return unmarshal(body, into)
+	}
+
+	body = io.TeeReader(body, raw)
+
+	err = unmarshal(body, into)
+	if err != nil {
+		return err
+	}
+
+	// The decoder stops at the end of the JSON value; keep anything following it as well.
+	_, err = io.Copy(ioutil.Discard, body)
+	return err
 }
 
 func decompress(response *http.Response) (io.Reader, error) {
@@ -272,18 +758,62 @@ func decompress(response *http.Response) (io.Reader, error) {
 		return response.Body, nil
 	}
 
+	if reader, ok := gzipReaders.Get().(*gzip.Reader); ok {
+		err := reader.Reset(response.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		return reader, nil
+	}
+
 	return gzip.NewReader(response.Body)
 }
 
+// gzipReaders holds decompressors for reuse, as each one allocates sizable buffers.
+var gzipReaders sync.Pool
+
+// unmarshal streams the JSON value from body into into. An empty body leaves into unchanged.
 func unmarshal(body io.Reader, into interface{}) error {
-	bytes, err := ioutil.ReadAll(body)
-	if err != nil {
-		return err
+	err := json.NewDecoder(body).Decode(into)
+	if err == io.EOF {
+		return nil
 	}
 
-	if bytes == nil || len(bytes) < 1 {
-		return nil
+	return err
+}
+
+func (d *Client) maxResponseSize() int64 {
+	if d.MaxResponseSize != 0 {
+		return d.MaxResponseSize
+	}
+
+	return DefaultMaxResponseSize
+}
+
+// limitedReader fails with ErrResponseTooLarge once more than max bytes are read.
+type limitedReader struct {
+	reader    io.Reader
+	remaining int64
+	max       int64
+}
+
+func (r *limitedReader) Read(p []byte) (int, error) {
+	if r.remaining < 0 {
+		return 0, fmt.Errorf("%w: limit is %d bytes", ErrResponseTooLarge, r.max)
+	}
+
+	// Read one byte past the limit to detect bodies which exceed it.
+	if int64(len(p)) > r.remaining+1 {
+		p = p[:r.remaining+1]
+	}
+
+	n, err := r.reader.Read(p)
+	r.remaining -= int64(n)
+
+	if r.remaining < 0 {
+		return n, fmt.Errorf("%w: limit is %d bytes", ErrResponseTooLarge, r.max)
 	}
 
-	return json.Unmarshal(bytes, &into)
+	return n, err
 }
@@ -1,13 +1,17 @@
 package weatherkit
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
+	"sync"
 	"testing"
 )
 
@@ -27,6 +31,14 @@ func TestParseNextHourForecastResponse(t *testing.T) {
 	weather(t, "testdata/next_hour_forecast.json")
 }
 
+func TestParseHistoricalComparisonsResponse(t *testing.T) {
+	weather(t, "testdata/historical_comparisons.json")
+}
+
+func TestParseWeatherChangesResponse(t *testing.T) {
+	weather(t, "testdata/weather_changes.json")
+}
+
 func TestParseFullForecastResponse(t *testing.T) {
 	weather(t, "testdata/full_weather.json")
 }
@@ -36,15 +48,13 @@ func TestParseFullAvailabilityResponse(t *testing.T) {
 }
 
 func TestWeatherErrorResponse(t *testing.T) {
-	client := Client{}
-
 	server, expected, err := getMockServerWithFileData("testdata/weather_error.json", http.StatusNotFound)
 	if err != nil {
 		t.Error(err.Error())
 	}
 
 	defer server.Close()
-	BaseUrl = server.URL
+	client := Client{BaseURL: server.URL}
 
 	_, err = client.Weather(context.TODO(), "", WeatherRequest{})
 	if err == nil {
@@ -67,15 +77,13 @@ func TestWeatherErrorResponse(t *testing.T) {
 }
 
 func TestWeatherAlertErrorResponse(t *testing.T) {
-	client := Client{}
-
 	server, expected, err := getMockServerWithFileData("testdata/weather_alert_error.json", http.StatusBadRequest)
 	if err != nil {
 		t.Error(err.Error())
 	}
 
 	defer server.Close()
-	BaseUrl = server.URL
+	client := Client{BaseURL: server.URL}
 
 	_, err = client.Alert(context.TODO(), "", WeatherAlertRequest{})
 	if err == nil {
@@ -98,15 +106,13 @@ func TestWeatherAlertErrorResponse(t *testing.T) {
 }
 
 func TestAttributionResponse(t *testing.T) {
-	client := Client{}
-
 	server, expected, err := getMockServerWithFileData("testdata/attribution.json", http.StatusOK)
 	if err != nil {
 		t.Error(err.Error())
 	}
 
 	defer server.Close()
-	BaseUrl = server.URL
+	client := Client{BaseURL: server.URL}
 
 	response, err := client.Attribution(context.TODO(), AttributionRequest{
 		Language: "en",
@@ -123,26 +129,220 @@ func TestAttributionResponse(t *testing.T) {
 	assertJsonEqual(t, expected, actual)
 }
 
+func TestConcurrentClientsWithDifferentBaseURLs(t *testing.T) {
+	first, expectedFirst, err := getMockServerWithFileData("testdata/current_weather.json", http.StatusOK)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer first.Close()
+
+	second, expectedSecond, err := getMockServerWithFileData("testdata/forecast_daily.json", http.StatusOK)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer second.Close()
+
+	clients := []struct {
+		client   *Client
+		expected []byte
+	}{
+		{&Client{BaseURL: first.URL}, expectedFirst},
+		{&Client{BaseURL: second.URL + "/"}, expectedSecond},
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		for _, c := range clients {
+			wg.Add(1)
+			go func(client *Client, expected []byte) {
+				defer wg.Done()
+
+				response, err := client.Weather(context.TODO(), "", WeatherRequest{})
+				if err != nil {
+					t.Error(err.Error())
+					return
+				}
+
+				actual, err := json.Marshal(response)
+				if err != nil {
+					t.Error(err.Error())
+					return
+				}
+
+				assertJsonEqual(t, expected, actual)
+			}(c.client, c.expected)
+		}
+	}
+
+	wg.Wait()
+}
+
+func TestCredentialedClientBaseURLOverridesClient(t *testing.T) {
+	cc := NewCredentialedClient(Credentials{}, WithClient(&Client{BaseURL: "https://client.example"}))
+	if have := cc.baseURL(); have != "https://client.example" {
+		t.Errorf("want: %s, have: %s", "https://client.example", have)
+	}
+
+	cc = NewCredentialedClient(Credentials{}, WithClient(&Client{BaseURL: "https://client.example"}), WithBaseURL("https://proxy.example/"))
+	if have := cc.baseURL(); have != "https://proxy.example" {
+		t.Errorf("want: %s, have: %s", "https://proxy.example", have)
+	}
+
+	cc = NewCredentialedClient(Credentials{})
+	if have := cc.baseURL(); have != DefaultBaseURL {
+		t.Errorf("want: %s, have: %s", DefaultBaseURL, have)
+	}
+}
+
+func TestClientIgnoresDeprecatedBaseUrl(t *testing.T) {
+	BaseUrl = "https://deprecated.example"
+	defer func() { BaseUrl = DefaultBaseURL }()
+
+	if have := (&Client{}).baseURL(); have != DefaultBaseURL {
+		t.Errorf("want: %s, have: %s", DefaultBaseURL, have)
+	}
+}
+
+func TestMaxResponseSize(t *testing.T) {
+	server, data, err := getMockServerWithFileData("testdata/full_weather.json", http.StatusOK)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer server.Close()
+
+	client := Client{BaseURL: server.URL, MaxResponseSize: int64(len(data)) / 2}
+
+	_, err = client.Weather(context.TODO(), "", WeatherRequest{})
+	if !errors.Is(err, ErrResponseTooLarge) || !errors.Is(err, ErrDecode) {
+		t.Fatalf("expected %v, got: %v", ErrResponseTooLarge, err)
+	}
+
+	for _, size := range []int64{int64(len(data)), -1} {
+		client := Client{BaseURL: server.URL, MaxResponseSize: size}
+
+		_, err = client.Weather(context.TODO(), "", WeatherRequest{})
+		if err != nil {
+			t.Errorf("expected a response within %d bytes, got: %v", size, err)
+		}
+	}
+}
+
+func TestMaxResponseSizeAppliesToDecompressedBody(t *testing.T) {
+	data, err := ioutil.ReadFile("testdata/full_weather.json")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	compressed := gzipBytes(t, data)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		_, _ = w.Write(compressed)
+	}))
+	defer server.Close()
+
+	client := Client{BaseURL: server.URL, MaxResponseSize: int64(len(compressed)) * 2}
+
+	_, err = client.Weather(context.TODO(), "", WeatherRequest{})
+	if !errors.Is(err, ErrResponseTooLarge) {
+		t.Fatalf("expected %v, got: %v", ErrResponseTooLarge, err)
+	}
+}
+
+func BenchmarkDecode(b *testing.B) {
+	data, err := ioutil.ReadFile("testdata/full_weather.json")
+	if err != nil {
+		b.Fatal(err.Error())
+	}
+
+	compressed := gzipBytes(b, data)
+
+	bodies := map[string]struct {
+		body     []byte
+		encoding string
+	}{
+		"plain": {data, ""},
+		"gzip":  {compressed, "gzip"},
+	}
+
+	for name, body := range bodies {
+		response := func() *http.Response {
+			header := http.Header{}
+			if len(body.encoding) > 0 {
+				header.Set("Content-Encoding", body.encoding)
+			}
+
+			return &http.Response{Header: header, Body: ioutil.NopCloser(bytes.NewReader(body.body))}
+		}
+
+		b.Run(name+"/stream", func(b *testing.B) {
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				err := decode(response(), &WeatherResponse{}, DefaultMaxResponseSize, nil)
+				if err != nil {
+					b.Fatal(err.Error())
+				}
+			}
+		})
+
+		b.Run(name+"/readall", func(b *testing.B) {
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				reader, err := decompress(response())
+				if err != nil {
+					b.Fatal(err.Error())
+				}
+
+				all, err := ioutil.ReadAll(reader)
+				if err != nil {
+					b.Fatal(err.Error())
+				}
+
+				err = json.Unmarshal(all, &WeatherResponse{})
+				if err != nil {
+					b.Fatal(err.Error())
+				}
+			}
+		})
+	}
+}
+
+func gzipBytes(tb testing.TB, data []byte) []byte {
+	buf := &bytes.Buffer{}
+	writer := gzip.NewWriter(buf)
+
+	_, err := writer.Write(data)
+	if err == nil {
+		err = writer.Close()
+	}
+	if err != nil {
+		tb.Fatal(err.Error())
+	}
+
+	return buf.Bytes()
+}
+
 func weather(t *testing.T, filename string) {
 	pk, err := createPrivateKeyPEM()
 	if err != nil {
 		t.Error(err)
 	}
 
-	client := NewCredentialedClient(Credentials{
-		KeyID:      "key",
-		TeamID:     "team",
-		ServiceID:  "service",
-		PrivateKey: pk,
-	})
-
 	server, expected, err := getMockServerWithFileData(filename, http.StatusOK)
 	if err != nil {
 		t.Error(err.Error())
 	}
 
 	defer server.Close()
-	BaseUrl = server.URL
+
+	client := NewCredentialedClient(Credentials{
+		KeyID:      "key",
+		TeamID:     "team",
+		ServiceID:  "service",
+		PrivateKey: pk,
+	}, WithBaseURL(server.URL))
 
 	response, err := client.Weather(context.TODO(), WeatherRequest{})
 	if err != nil {
@@ -163,20 +363,19 @@ func availability(t *testing.T, filename string) {
 		t.Error(err)
 	}
 
-	client := NewCredentialedClient(Credentials{
-		KeyID:      "key",
-		TeamID:     "team",
-		ServiceID:  "service",
-		PrivateKey: pk,
-	})
-
 	server, expected, err := getMockServerWithFileData(filename, http.StatusOK)
 	if err != nil {
 		t.Error(err.Error())
 	}
 
 	defer server.Close()
-	BaseUrl = server.URL
+
+	client := NewCredentialedClient(Credentials{
+		KeyID:      "key",
+		TeamID:     "team",
+		ServiceID:  "service",
+		PrivateKey: pk,
+	}, WithBaseURL(server.URL))
 
 	response, err := client.Availability(context.TODO(), AvailabilityRequest{})
 	if err != nil {
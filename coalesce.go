@@ -0,0 +1,118 @@
+package weatherkit
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// coalescer shares a single in-flight call between concurrent callers with the same key.
+// The zero value is ready to use.
+type coalescer struct {
+	mu      sync.Mutex
+	flights map[string]*flight
+}
+
+type flight struct {
+	done    chan struct{}
+	cancel  context.CancelFunc
+	waiters int
+
+	// The JSON encoded result, decoded separately by every waiter so each receives its own copy.
+	value []byte
+	err   error
+}
+
+// do joins the in-flight call for key or starts a new one with fn.
+// A caller whose context is done stops waiting without affecting the other waiters;
+// the call itself is only canceled once every waiter has stopped waiting.
+func (c *coalescer) do(ctx context.Context, key string, output interface{}, fn func(ctx context.Context) (interface{}, error)) error {
+	c.mu.Lock()
+
+	if c.flights == nil {
+		c.flights = map[string]*flight{}
+	}
+
+	f, ok := c.flights[key]
+	if !ok {
+		shared, cancel := context.WithCancel(detachedContext{parent: ctx})
+
+		f = &flight{
+			done:   make(chan struct{}),
+			cancel: cancel,
+		}
+		c.flights[key] = f
+
+		go c.run(shared, key, f, fn)
+	}
+
+	f.waiters++
+	c.mu.Unlock()
+
+	select {
+	case <-f.done:
+		if f.err != nil {
+			return f.err
+		}
+
+		return json.Unmarshal(f.value, output)
+	case <-ctx.Done():
+		c.leave(key, f)
+		return ctx.Err()
+	}
+}
+
+func (c *coalescer) run(ctx context.Context, key string, f *flight, fn func(ctx context.Context) (interface{}, error)) {
+	value, err := fn(ctx)
+	if err == nil {
+		f.value, err = json.Marshal(value)
+	}
+	f.err = err
+
+	c.mu.Lock()
+	if c.flights[key] == f {
+		delete(c.flights, key)
+	}
+	c.mu.Unlock()
+
+	f.cancel()
+	close(f.done)
+}
+
+func (c *coalescer) leave(key string, f *flight) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	f.waiters--
+	if f.waiters > 0 {
+		return
+	}
+
+	if c.flights[key] == f {
+		delete(c.flights, key)
+	}
+
+	f.cancel()
+}
+
+// detachedContext keeps the values of its parent but none of its deadline or cancellation.
+type detachedContext struct {
+	parent context.Context
+}
+
+func (c detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (c detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (c detachedContext) Err() error {
+	return nil
+}
+
+func (c detachedContext) Value(key interface{}) interface{} {
+	return c.parent.Value(key)
+}
@@ -0,0 +1,157 @@
+package weatherkit
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCoalesceIdenticalWeatherRequests(t *testing.T) {
+	server, calls, release := getBlockingMockServer(t, "testdata/current_weather.json")
+	defer server.Close()
+
+	client := &Client{BaseURL: server.URL, Coalesce: true}
+	request := WeatherRequest{Language: "en", DataSets: DataSets{DataSetCurrentWeather}}
+
+	const callers = 5
+	responses := make([]*WeatherResponse, callers)
+
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			response, err := client.Weather(context.TODO(), "", request)
+			if err != nil {
+				t.Error(err.Error())
+			}
+			responses[i] = response
+		}(i)
+	}
+
+	waitForWaiters(t, client, request.url(server.URL), callers)
+	close(release)
+	wg.Wait()
+
+	if have := atomic.LoadInt32(calls); have != 1 {
+		t.Errorf("want: %d calls, have: %d", 1, have)
+	}
+
+	responses[0].CurrentWeather.Temperature = -100
+
+	for _, response := range responses[1:] {
+		if response.CurrentWeather == nil || response.CurrentWeather.Temperature == -100 {
+			t.Errorf("expected an independent copy of the response, have: %+v", response.CurrentWeather)
+		}
+	}
+}
+
+func TestCoalesceCallerCancellationDoesNotAbortOthers(t *testing.T) {
+	server, calls, release := getBlockingMockServer(t, "testdata/current_weather.json")
+	defer server.Close()
+
+	client := &Client{BaseURL: server.URL, Coalesce: true}
+	request := WeatherRequest{Language: "en", DataSets: DataSets{DataSetCurrentWeather}}
+
+	canceled, cancel := context.WithCancel(context.Background())
+
+	errs := make(chan error, 2)
+	go func() {
+		_, err := client.Weather(canceled, "", request)
+		errs <- err
+	}()
+	go func() {
+		_, err := client.Weather(context.Background(), "", request)
+		errs <- err
+	}()
+
+	waitForWaiters(t, client, request.url(server.URL), 2)
+	cancel()
+
+	if err := <-errs; !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the canceled caller to stop waiting, got: %v", err)
+	}
+
+	close(release)
+
+	if err := <-errs; err != nil {
+		t.Errorf("expected the remaining caller to succeed, got: %v", err)
+	}
+
+	if have := atomic.LoadInt32(calls); have != 1 {
+		t.Errorf("want: %d calls, have: %d", 1, have)
+	}
+}
+
+func TestCoalesceCancelsWhenAllCallersLeave(t *testing.T) {
+	aborted := make(chan struct{})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+		close(aborted)
+	}))
+	defer server.Close()
+
+	client := &Client{BaseURL: server.URL, Coalesce: true}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := client.Weather(ctx, "", WeatherRequest{})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got: %v", err)
+	}
+
+	select {
+	case <-aborted:
+	case <-time.After(5 * time.Second):
+		t.Errorf("expected the shared request to be canceled")
+	}
+}
+
+// getBlockingMockServer serves the file once release is closed, counting the requests it receives.
+func getBlockingMockServer(t *testing.T, filename string) (*httptest.Server, *int32, chan struct{}) {
+	bytes, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	calls := int32(0)
+	release := make(chan struct{})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(bytes)
+	}))
+
+	return server, &calls, release
+}
+
+func waitForWaiters(t *testing.T, client *Client, key string, waiters int) {
+	deadline := time.Now().Add(5 * time.Second)
+
+	for time.Now().Before(deadline) {
+		client.flights.mu.Lock()
+		f, ok := client.flights.flights[key]
+		joined := ok && f.waiters == waiters
+		client.flights.mu.Unlock()
+
+		if joined {
+			return
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	t.Fatalf("timed out waiting for %d callers to join", waiters)
+}
@@ -1,8 +1,13 @@
 package weatherkit
 
 import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/golang-jwt/jwt/v4"
@@ -23,17 +28,60 @@ type Credentials struct {
 
 	// ServiceID is the Service ID from your developer account.
 	ServiceID string
+
+	// parsed holds the key parsed from PrivateKey, shared by copies of the credentials.
+	// Credentials without it parse PrivateKey for every token.
+	parsed *parsedKey
+}
+
+// NewCredentials validates the credentials and parses the PEM encoded private key once, so signing
+// a token does not parse it again. The key must be an ECDSA key on the P-256 curve.
+// The key is parsed again if PrivateKey is changed later.
+func NewCredentials(keyID string, teamID string, serviceID string, privateKey []byte) (Credentials, error) {
+	credentials := Credentials{
+		PrivateKey: privateKey,
+		KeyID:      keyID,
+		TeamID:     teamID,
+		ServiceID:  serviceID,
+	}
+
+	err := credentials.validate(defaultTokenDuration)
+	if err != nil {
+		return Credentials{}, err
+	}
+
+	credentials.parsed = &parsedKey{}
+
+	_, err = credentials.privateKey()
+	if err != nil {
+		return Credentials{}, err
+	}
+
+	return credentials, nil
 }
 
 // SignedJWT generates a valid JWT signed with your PEM private key.
 // Returns the string JWT along with its expiration time.
 func (c *Credentials) SignedJWT(validFor time.Duration) (string, time.Time, error) {
+	return c.signedJWT(validFor, nil)
+}
+
+// signedJWT generates a JWT signed with signer, or with the parsed PrivateKey if signer is nil.
+func (c *Credentials) signedJWT(validFor time.Duration, signer crypto.Signer) (string, time.Time, error) {
 	token, exp, err := c.create(validFor)
 	if err != nil {
 		return "", time.Time{}, err
 	}
 
-	signed, err := c.sign(token)
+	if signer == nil {
+		privateKey, err := c.privateKey()
+		if err != nil {
+			return "", time.Time{}, err
+		}
+		signer = privateKey
+	}
+
+	signed, err := sign(token, signer)
 	if err != nil {
 		return "", time.Time{}, err
 	}
@@ -66,18 +114,68 @@ func (c *Credentials) create(validFor time.Duration) (*jwt.Token, time.Time, err
 	}, exp, nil
 }
 
-func (c *Credentials) sign(token *jwt.Token) (string, error) {
-	privateKey, err := jwt.ParseECPrivateKeyFromPEM(c.PrivateKey)
+func sign(token *jwt.Token, signer crypto.Signer) (string, error) {
+	signingString, err := token.SigningString()
 	if err != nil {
-		return "", fmt.Errorf("failed to parse private key. %s", err)
+		return "", fmt.Errorf("failed to create signed JWT. %s", err)
 	}
 
-	signedJwt, err := token.SignedString(privateKey)
+	signature, err := signES256(signer, signingString)
 	if err != nil {
 		return "", fmt.Errorf("failed to create signed JWT. %s", err)
 	}
 
-	return signedJwt, nil
+	return signingString + "." + jwt.EncodeSegment(signature), nil
+}
+
+// privateKey returns the parsed PrivateKey, reusing the key parsed by NewCredentials while PrivateKey is unchanged.
+func (c *Credentials) privateKey() (*ecdsa.PrivateKey, error) {
+	if c.parsed == nil {
+		return parsePrivateKey(c.PrivateKey)
+	}
+
+	return c.parsed.get(c.PrivateKey)
+}
+
+// parsedKey is a private key along with the PEM bytes it was parsed from.
+type parsedKey struct {
+	mu   sync.Mutex
+	data []byte
+	key  *ecdsa.PrivateKey
+}
+
+// get returns the key parsed from data, parsing it again when data differs from the bytes it was parsed from.
+func (p *parsedKey) get(data []byte) (*ecdsa.PrivateKey, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.key != nil && bytes.Equal(p.data, data) {
+		return p.key, nil
+	}
+
+	key, err := parsePrivateKey(data)
+	if err != nil {
+		return nil, err
+	}
+
+	p.key = key
+	p.data = append([]byte{}, data...)
+
+	return key, nil
+}
+
+// parsePrivateKey parses a PEM encoded ECDSA private key on the P-256 curve required by ES256.
+func parsePrivateKey(data []byte) (*ecdsa.PrivateKey, error) {
+	privateKey, err := jwt.ParseECPrivateKeyFromPEM(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key. %s", err)
+	}
+
+	if privateKey.Curve != elliptic.P256() {
+		return nil, fmt.Errorf("failed to parse private key. the key must use the P-256 curve, have: %s", privateKey.Curve.Params().Name)
+	}
+
+	return privateKey, nil
 }
 
 func (c *Credentials) validate(validFor time.Duration) error {
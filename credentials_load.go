@@ -0,0 +1,224 @@
+package weatherkit
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Environment variables read by CredentialsFromEnv.
+const (
+	// The Key identifier from your developer account.
+	EnvKeyID = "WEATHERKIT_KEY_ID"
+
+	// The Team ID from your developer account.
+	EnvTeamID = "WEATHERKIT_TEAM_ID"
+
+	// The Service ID from your developer account.
+	EnvServiceID = "WEATHERKIT_SERVICE_ID"
+
+	// The path to the .p8 private key file.
+	EnvPrivateKeyPath = "WEATHERKIT_PRIVATE_KEY_PATH"
+
+	// The PEM encoded private key, either as is or base64 encoded.
+	EnvPrivateKey = "WEATHERKIT_PRIVATE_KEY"
+)
+
+// CredentialsFromEnv loads credentials from the EnvKeyID, EnvTeamID, EnvServiceID environment variables
+// and either EnvPrivateKeyPath or EnvPrivateKey. When EnvKeyID is not set, the key identifier is inferred
+// from a key path named AuthKey_<KEYID>.p8, as downloaded from your developer account.
+func CredentialsFromEnv() (Credentials, error) {
+	return credentialSettings{
+		keyID:          setting{EnvKeyID, os.Getenv(EnvKeyID)},
+		teamID:         setting{EnvTeamID, os.Getenv(EnvTeamID)},
+		serviceID:      setting{EnvServiceID, os.Getenv(EnvServiceID)},
+		privateKeyPath: setting{EnvPrivateKeyPath, os.Getenv(EnvPrivateKeyPath)},
+		privateKey:     setting{EnvPrivateKey, os.Getenv(EnvPrivateKey)},
+	}.credentials()
+}
+
+// CredentialsFromFile loads credentials from a file of "name = value" lines. Blank lines and lines
+// starting with # are ignored, and values may be wrapped in double quotes. The names are key_id, team_id,
+// service_id, and either private_key_path or a base64 encoded private_key. A relative private_key_path is
+// resolved from the directory of the file. For example:
+//
+//	key_id = ABCDE12345
+//	team_id = TEAM123456
+//	service_id = com.example.weather
+//	private_key_path = AuthKey_ABCDE12345.p8
+//
+// When key_id is not set, the key identifier is inferred from a key path named AuthKey_<KEYID>.p8.
+func CredentialsFromFile(path string) (Credentials, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("failed to read credentials file. %s", err)
+	}
+
+	values, err := parseSettings(data)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("invalid credentials file %s: %s", path, err)
+	}
+
+	keyPath := values["private_key_path"]
+	if len(keyPath) > 0 && !filepath.IsAbs(keyPath) {
+		keyPath = filepath.Join(filepath.Dir(path), keyPath)
+	}
+
+	return credentialSettings{
+		keyID:          setting{"key_id", values["key_id"]},
+		teamID:         setting{"team_id", values["team_id"]},
+		serviceID:      setting{"service_id", values["service_id"]},
+		privateKeyPath: setting{"private_key_path", keyPath},
+		privateKey:     setting{"private_key", values["private_key"]},
+	}.credentials()
+}
+
+// CredentialsFromKeyFile loads credentials from the .p8 private key file at path, inferring the key
+// identifier from its AuthKey_<KEYID>.p8 file name.
+func CredentialsFromKeyFile(path string, teamID string, serviceID string) (Credentials, error) {
+	return credentialSettings{
+		keyID:          setting{"key identifier", ""},
+		teamID:         setting{"team ID", teamID},
+		serviceID:      setting{"service ID", serviceID},
+		privateKeyPath: setting{"private key path", path},
+		privateKey:     setting{"private key", ""},
+	}.credentials()
+}
+
+// setting is a named credential value, named after its environment variable or configuration key.
+type setting struct {
+	name  string
+	value string
+}
+
+type credentialSettings struct {
+	keyID          setting
+	teamID         setting
+	serviceID      setting
+	privateKeyPath setting
+	privateKey     setting
+}
+
+// credentials validates the settings and loads the private key. Errors name the settings
+// but never include the private key.
+func (s credentialSettings) credentials() (Credentials, error) {
+	messages := []string{}
+	keyID := s.keyID.value
+
+	var privateKey []byte
+	var err error
+
+	switch {
+	case len(s.privateKeyPath.value) > 0 && len(s.privateKey.value) > 0:
+		messages = append(messages, fmt.Sprintf("only one of %s and %s may be set", s.privateKeyPath.name, s.privateKey.name))
+	case len(s.privateKeyPath.value) > 0:
+		privateKey, err = ioutil.ReadFile(s.privateKeyPath.value)
+		if err != nil {
+			messages = append(messages, fmt.Sprintf("failed to read %s. %s", s.privateKeyPath.name, err))
+		}
+
+		if len(keyID) < 1 {
+			keyID = keyIDFromPath(s.privateKeyPath.value)
+		}
+	case len(s.privateKey.value) > 0:
+		privateKey, err = decodePrivateKey(s.privateKey.value)
+		if err != nil {
+			messages = append(messages, fmt.Sprintf("%s %s", s.privateKey.name, err))
+		}
+	default:
+		messages = append(messages, fmt.Sprintf("%s or %s must be set", s.privateKeyPath.name, s.privateKey.name))
+	}
+
+	if len(keyID) < 1 {
+		messages = append(messages, fmt.Sprintf("%s may not be empty unless the private key file is named AuthKey_<KEYID>.p8", s.keyID.name))
+	}
+
+	if len(s.teamID.value) < 1 {
+		messages = append(messages, fmt.Sprintf("%s may not be empty", s.teamID.name))
+	}
+
+	if len(s.serviceID.value) < 1 {
+		messages = append(messages, fmt.Sprintf("%s may not be empty", s.serviceID.name))
+	}
+
+	if len(messages) > 0 {
+		return Credentials{}, fmt.Errorf("validation failed: %s", strings.Join(messages, ", "))
+	}
+
+	return NewCredentials(keyID, s.teamID.value, s.serviceID.value, privateKey)
+}
+
+// keyIDFromPath returns the key identifier of a private key file named AuthKey_<KEYID>.p8, or an empty string.
+func keyIDFromPath(path string) string {
+	name := filepath.Base(path)
+	if !strings.HasPrefix(name, "AuthKey_") || !strings.HasSuffix(name, ".p8") {
+		return ""
+	}
+
+	return strings.TrimSuffix(strings.TrimPrefix(name, "AuthKey_"), ".p8")
+}
+
+// decodePrivateKey decodes an inline private key: PEM, PEM with escaped newlines, or base64 encoded PEM.
+func decodePrivateKey(value string) ([]byte, error) {
+	value = strings.TrimSpace(value)
+
+	if strings.HasPrefix(value, "-----BEGIN") {
+		return []byte(strings.ReplaceAll(value, `\n`, "\n")), nil
+	}
+
+	compact := strings.Join(strings.Fields(value), "")
+
+	for _, encoding := range []*base64.Encoding{base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding} {
+		decoded, err := encoding.DecodeString(compact)
+		if err == nil && bytes.HasPrefix(bytes.TrimSpace(decoded), []byte("-----BEGIN")) {
+			return decoded, nil
+		}
+	}
+
+	return nil, fmt.Errorf("must be PEM encoded or base64 encoded PEM")
+}
+
+// parseSettings parses "name = value" lines. Errors refer to line numbers rather than their content,
+// which may hold secrets.
+func parseSettings(data []byte) (map[string]string, error) {
+	values := map[string]string{}
+	known := map[string]bool{"key_id": true, "team_id": true, "service_id": true, "private_key_path": true, "private_key": true}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 4096), 1<<20)
+
+	for number := 1; scanner.Scan(); number++ {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) < 1 || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		name, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected name = value", number)
+		}
+
+		name = strings.TrimSpace(name)
+		if !known[name] {
+			return nil, fmt.Errorf("line %d: unknown setting", number)
+		}
+
+		if _, ok := values[name]; ok {
+			return nil, fmt.Errorf("line %d: %s is set more than once", number, name)
+		}
+
+		value = strings.TrimSpace(value)
+		if len(value) > 1 && strings.HasPrefix(value, `"`) && strings.HasSuffix(value, `"`) {
+			value = value[1 : len(value)-1]
+		}
+
+		values[name] = value
+	}
+
+	return values, scanner.Err()
+}
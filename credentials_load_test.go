@@ -0,0 +1,218 @@
+package weatherkit
+
+import (
+	"encoding/base64"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeKeyFile(t *testing.T, dir string, name string) (string, []byte) {
+	pk, err := createPrivateKeyPEM()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	path := filepath.Join(dir, name)
+
+	err = ioutil.WriteFile(path, pk, 0600)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	return path, pk
+}
+
+func assertCredentials(t *testing.T, credentials Credentials, err error, keyID string) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if credentials.KeyID != keyID || credentials.TeamID != "team" || credentials.ServiceID != "service" {
+		t.Errorf("unexpected credentials: %s %s %s", credentials.KeyID, credentials.TeamID, credentials.ServiceID)
+	}
+}
+
+func TestCredentialsFromEnv(t *testing.T) {
+	path, pk := writeKeyFile(t, t.TempDir(), "AuthKey_ABCDE12345.p8")
+
+	t.Setenv(EnvKeyID, "")
+	t.Setenv(EnvPrivateKey, "")
+	t.Setenv(EnvTeamID, "team")
+	t.Setenv(EnvServiceID, "service")
+	t.Setenv(EnvPrivateKeyPath, path)
+
+	credentials, err := CredentialsFromEnv()
+	assertCredentials(t, credentials, err, "ABCDE12345")
+
+	t.Setenv(EnvKeyID, "key")
+
+	credentials, err = CredentialsFromEnv()
+	assertCredentials(t, credentials, err, "key")
+
+	t.Setenv(EnvPrivateKeyPath, "")
+
+	inline := map[string]string{
+		"pem":            string(pk),
+		"escaped pem":    strings.ReplaceAll(string(pk), "\n", `\n`),
+		"base64":         base64.StdEncoding.EncodeToString(pk),
+		"raw url base64": base64.RawURLEncoding.EncodeToString(pk),
+		"wrapped base64": strings.Join(strings.SplitAfter(base64.StdEncoding.EncodeToString(pk), "A"), "\n"),
+	}
+
+	for name, value := range inline {
+		t.Setenv(EnvPrivateKey, value)
+
+		credentials, err = CredentialsFromEnv()
+		if err != nil {
+			t.Errorf("%s: %s", name, err)
+			continue
+		}
+		assertCredentials(t, credentials, err, "key")
+	}
+}
+
+func TestCredentialsFromEnvValidation(t *testing.T) {
+	path, pk := writeKeyFile(t, t.TempDir(), "key.p8")
+
+	for _, name := range []string{EnvKeyID, EnvTeamID, EnvServiceID} {
+		t.Setenv(name, "")
+	}
+
+	t.Setenv(EnvPrivateKeyPath, path)
+	t.Setenv(EnvPrivateKey, base64.StdEncoding.EncodeToString(pk))
+
+	_, err := CredentialsFromEnv()
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	for _, want := range []string{"only one of " + EnvPrivateKeyPath + " and " + EnvPrivateKey, EnvKeyID, EnvTeamID, EnvServiceID} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected the error to contain %q, have: %s", want, err)
+		}
+	}
+
+	t.Setenv(EnvPrivateKeyPath, "")
+	t.Setenv(EnvPrivateKey, "")
+
+	_, err = CredentialsFromEnv()
+	if err == nil || !strings.Contains(err.Error(), EnvPrivateKeyPath+" or "+EnvPrivateKey+" must be set") {
+		t.Errorf("expected a missing key error, got: %v", err)
+	}
+}
+
+func TestCredentialsErrorsOmitSecrets(t *testing.T) {
+	_, pk := writeKeyFile(t, t.TempDir(), "key.p8")
+
+	body := strings.Split(string(pk), "\n")[1]
+	secrets := map[string]string{
+		"not pem":       "c2VjcmV0LXZhbHVlLXRoYXQtaXMtbm90LWEta2V5",
+		"truncated pem": string(pk[:len(pk)/2]),
+		"corrupt pem":   strings.Replace(string(pk), body[:8], "########", 1),
+	}
+
+	t.Setenv(EnvKeyID, "key")
+	t.Setenv(EnvTeamID, "team")
+	t.Setenv(EnvServiceID, "service")
+	t.Setenv(EnvPrivateKeyPath, "")
+
+	for name, secret := range secrets {
+		t.Setenv(EnvPrivateKey, secret)
+
+		_, err := CredentialsFromEnv()
+		if err == nil {
+			t.Errorf("%s: expected an error", name)
+			continue
+		}
+
+		if strings.Contains(err.Error(), secret) || strings.Contains(err.Error(), body[8:24]) {
+			t.Errorf("%s: the error echoes the private key: %s", name, err)
+		}
+	}
+
+	dir := t.TempDir()
+	config := filepath.Join(dir, "weatherkit.conf")
+
+	err := ioutil.WriteFile(config, []byte("team_id = team\n"+body+"\n"), 0600)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	_, err = CredentialsFromFile(config)
+	if err == nil || strings.Contains(err.Error(), body) || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("expected the malformed line to be reported by number only, got: %v", err)
+	}
+}
+
+func TestCredentialsFromFile(t *testing.T) {
+	dir := t.TempDir()
+	_, pk := writeKeyFile(t, dir, "AuthKey_ABCDE12345.p8")
+
+	files := map[string]string{
+		"relative path": `
+# WeatherKit credentials
+team_id = team
+service_id = "service"
+private_key_path = AuthKey_ABCDE12345.p8
+`,
+		"inline key": "key_id=ABCDE12345\nteam_id=team\nservice_id=service\nprivate_key=" + base64.StdEncoding.EncodeToString(pk) + "\n",
+	}
+
+	for name, contents := range files {
+		config := filepath.Join(dir, "weatherkit.conf")
+
+		err := ioutil.WriteFile(config, []byte(contents), 0600)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		credentials, err := CredentialsFromFile(config)
+		if err != nil {
+			t.Errorf("%s: %s", name, err)
+			continue
+		}
+		assertCredentials(t, credentials, err, "ABCDE12345")
+	}
+
+	invalid := map[string]string{
+		"unknown":   "team_id = team\nteam = team\n",
+		"duplicate": "team_id = team\nteam_id = other\n",
+		"missing":   "team_id = team\nservice_id = service\n",
+	}
+
+	for name, contents := range invalid {
+		config := filepath.Join(dir, name+".conf")
+
+		err := ioutil.WriteFile(config, []byte(contents), 0600)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		if _, err := CredentialsFromFile(config); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	if _, err := CredentialsFromFile(filepath.Join(dir, "missing.conf")); err == nil {
+		t.Errorf("expected a missing file to error")
+	}
+}
+
+func TestCredentialsFromKeyFile(t *testing.T) {
+	dir := t.TempDir()
+	path, _ := writeKeyFile(t, dir, "AuthKey_ABCDE12345.p8")
+
+	credentials, err := CredentialsFromKeyFile(path, "team", "service")
+	assertCredentials(t, credentials, err, "ABCDE12345")
+
+	other, _ := writeKeyFile(t, dir, "key.p8")
+
+	_, err = CredentialsFromKeyFile(other, "team", "service")
+	if err == nil || !strings.Contains(err.Error(), "AuthKey_<KEYID>.p8") {
+		t.Errorf("expected the key identifier to be required, got: %v", err)
+	}
+}
@@ -1,11 +1,15 @@
 package weatherkit
 
 import (
+	"context"
 	"crypto/ecdsa"
 	"crypto/elliptic"
 	"crypto/rand"
+	"crypto/rsa"
 	"crypto/x509"
 	"encoding/pem"
+	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -84,3 +88,191 @@ func createPrivateKeyPEM() ([]byte, error) {
 
 	return pem.EncodeToMemory(block), nil
 }
+
+func TestNewCredentials(t *testing.T) {
+	pk, err := createPrivateKeyPEM()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	credentials, err := NewCredentials("key", "team", "service", pk)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if credentials.parsed == nil || credentials.parsed.key == nil {
+		t.Fatal("expected the private key to be parsed")
+	}
+
+	signed, _, err := credentials.SignedJWT(time.Minute)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	assertSignedWith(t, signed, pk)
+}
+
+func TestNewCredentialsParsesChangedKey(t *testing.T) {
+	first, err := createPrivateKeyPEM()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	second, err := createPrivateKeyPEM()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	credentials, err := NewCredentials("key", "team", "service", first)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	credentials.PrivateKey = second
+
+	signed, _, err := credentials.SignedJWT(time.Minute)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	assertSignedWith(t, signed, second)
+
+	credentials.PrivateKey = []byte("invalid")
+
+	_, _, err = credentials.SignedJWT(time.Minute)
+	if err == nil || !strings.Contains(err.Error(), "failed to parse private key") {
+		t.Errorf("expected an invalid key to be reported when signing, got: %v", err)
+	}
+}
+
+func TestNewCredentialsRejectsInvalidKeys(t *testing.T) {
+	pk, err := createPrivateKeyPEM()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	p384, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	marshalled, err := x509.MarshalECPrivateKey(p384)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	tests := map[string]struct {
+		keyID string
+		key   []byte
+		want  string
+	}{
+		"malformed pem":  {"key", []byte("not a pem key"), "failed to parse private key"},
+		"truncated pem":  {"key", pk[:len(pk)/2], "failed to parse private key"},
+		"p-384":          {"key", pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: marshalled}), "P-256"},
+		"rsa":            {"key", pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)}), "failed to parse private key"},
+		"missing key id": {"", pk, "key identifier may not be empty"},
+	}
+
+	for name, test := range tests {
+		_, err := NewCredentials(test.keyID, "team", "service", test.key)
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%s: expected an error containing %q, got: %v", name, test.want, err)
+		}
+	}
+}
+
+func TestSigningTokenSourceParsesChangedKey(t *testing.T) {
+	first, err := createPrivateKeyPEM()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	second, err := createPrivateKeyPEM()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	source := NewSigningTokenSource(Credentials{KeyID: "key", TeamID: "team", ServiceID: "service", PrivateKey: first}, 0)
+
+	signed, _, err := source.Token(context.TODO())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	assertSignedWith(t, signed, first)
+
+	// Replacing the key in place must not keep signing with the key parsed before.
+	copy(first, second)
+
+	signed, _, err = source.Token(context.TODO())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	assertSignedWith(t, signed, second)
+
+	copy(first, "invalid")
+
+	_, _, err = source.Token(context.TODO())
+	if err == nil || !strings.Contains(err.Error(), "failed to parse private key") {
+		t.Errorf("expected an invalid key to be reported when signing, got: %v", err)
+	}
+}
+
+func TestCredentialedClientReportsInvalidKey(t *testing.T) {
+	client := NewCredentialedClient(Credentials{KeyID: "key", TeamID: "team", ServiceID: "service", PrivateKey: []byte("invalid")})
+
+	_, err := client.getToken(context.TODO())
+	if !errors.Is(err, ErrToken) {
+		t.Errorf("expected an invalid key to be reported when signing, got: %v", err)
+	}
+}
+
+func BenchmarkSignedJWT(b *testing.B) {
+	pk, err := createPrivateKeyPEM()
+	if err != nil {
+		b.Fatal(err.Error())
+	}
+
+	parsed, err := NewCredentials("key", "team", "service", pk)
+	if err != nil {
+		b.Fatal(err.Error())
+	}
+
+	credentials := map[string]Credentials{
+		"parse per token": {PrivateKey: pk, KeyID: "key", TeamID: "team", ServiceID: "service"},
+		"parsed once":     parsed,
+	}
+
+	for name, c := range credentials {
+		c := c
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				_, _, err := c.SignedJWT(time.Minute)
+				if err != nil {
+					b.Fatal(err.Error())
+				}
+			}
+		})
+	}
+}
+
+func assertSignedWith(t *testing.T, signed string, pk []byte) {
+	t.Helper()
+
+	key, err := jwt.ParseECPrivateKeyFromPEM(pk)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	_, err = jwt.Parse(signed, func(token *jwt.Token) (interface{}, error) {
+		return &key.PublicKey, nil
+	})
+	if err != nil {
+		t.Errorf("expected the token to verify with the key, got: %v", err)
+	}
+}
@@ -32,4 +32,10 @@ const (
 
 	// Weather alerts for the requested location.
 	DataSetWeatherAlerts DataSet = "weatherAlerts"
+
+	// How the forecast for the requested location compares to historical normals.
+	DataSetHistoricalComparisons DataSet = "historicalComparisons"
+
+	// Significant changes in the forecast for the requested location.
+	DataSetWeatherChanges DataSet = "weatherChanges"
 )
@@ -3,25 +3,49 @@ package weatherkit
 import (
 	"fmt"
 	"net/url"
+	"strings"
 )
 
+// DefaultBaseURL is the WeatherKit API base URL used when a client does not configure its own.
+const DefaultBaseURL = "https://weatherkit.apple.com"
+
 // WeatherKit API base URL
-var BaseUrl = "https://weatherkit.apple.com"
+//
+// Deprecated: Set Client.BaseURL or use WithBaseURL instead.
+// BaseUrl is no longer read: clients without a configured base URL use DefaultBaseURL.
+var BaseUrl = DefaultBaseURL
+
+// Endpoint identifies a WeatherKit API endpoint.
+type Endpoint string
+
+const (
+	// The weather data endpoint.
+	EndpointWeather Endpoint = "weather"
+
+	// The data set availability endpoint.
+	EndpointAvailability Endpoint = "availability"
+
+	// The weather alert details endpoint.
+	EndpointWeatherAlert Endpoint = "weatherAlert"
 
-func weatherEndpoint(lang string, latitude float64, longitude float64, values url.Values) string {
-	return BaseUrl + "/api/v1/weather/" + fmt.Sprintf("%s/%g/%g", lang, latitude, longitude) + encodeUrlParameters(values)
+	// The attribution branding endpoint.
+	EndpointAttribution Endpoint = "attribution"
+)
+
+func weatherEndpoint(base string, lang string, latitude float64, longitude float64, values url.Values) string {
+	return base + "/api/v1/weather/" + fmt.Sprintf("%s/%g/%g", lang, latitude, longitude) + encodeUrlParameters(values)
 }
 
-func availabilityEndpoint(latitude, longitude float64, values url.Values) string {
-	return BaseUrl + "/api/v1/availability/" + fmt.Sprintf("%g/%g", latitude, longitude) + encodeUrlParameters(values)
+func availabilityEndpoint(base string, latitude, longitude float64, values url.Values) string {
+	return base + "/api/v1/availability/" + fmt.Sprintf("%g/%g", latitude, longitude) + encodeUrlParameters(values)
 }
 
-func weatherAlertEndpoint(lang string, id string) string {
-	return BaseUrl + "/api/v1/weatherAlert/" + fmt.Sprintf("%s/%s", lang, id)
+func weatherAlertEndpoint(base string, lang string, id string) string {
+	return base + "/api/v1/weatherAlert/" + fmt.Sprintf("%s/%s", lang, id)
 }
 
-func attribution(lang string) string {
-	return BaseUrl + "/attribution/" + lang
+func attribution(base string, lang string) string {
+	return base + "/attribution/" + lang
 }
 
 func encodeUrlParameters(values url.Values) string {
@@ -34,6 +58,11 @@ func encodeUrlParameters(values url.Values) string {
 	return "?" + queryString
 }
 
+func normalizeBaseURL(base string) string {
+	return strings.TrimRight(base, "/")
+}
+
 type urlBuilder interface {
-	url() string
+	url(base string) string
+	endpoint() Endpoint
 }
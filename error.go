@@ -1,11 +1,49 @@
 package weatherkit
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"time"
 )
 
+// Errors matched by RestError through errors.Is, depending on the response status code.
+var (
+	// The request was malformed (400).
+	ErrBadRequest = errors.New("bad request")
+
+	// The developer token is missing, invalid or expired (401).
+	ErrUnauthorized = errors.New("unauthorized")
+
+	// The developer token is not allowed to access the resource (403).
+	ErrForbidden = errors.New("forbidden")
+
+	// The resource does not exist (404).
+	ErrNotFound = errors.New("not found")
+
+	// Too many requests were sent (429).
+	ErrRateLimited = errors.New("rate limited")
+
+	// The API failed to handle the request (5xx).
+	ErrServerError = errors.New("server error")
+)
+
+var (
+	// ErrDataSetUnavailable is matched by DataSetUnavailableError.
+	ErrDataSetUnavailable = errors.New("data set unavailable")
+
+	// ErrDecode is matched by DecodeError.
+	ErrDecode = errors.New("failed to decode response")
+
+	// ErrResponseTooLarge is wrapped by DecodeError when a response exceeds Client.MaxResponseSize.
+	ErrResponseTooLarge = errors.New("response body too large")
+
+	// ErrToken is matched by TokenError.
+	ErrToken = errors.New("failed to obtain developer token")
+)
+
 // ErrorResponse is returned in response to an API error.
 type ErrorResponse struct {
 	Timestamp *time.Time `json:"timestamp,omitempty"`
@@ -15,11 +53,171 @@ type ErrorResponse struct {
 	Path      string     `json:"path,omitempty"`
 }
 
+// MaxErrorBodySize is the maximum number of bytes of a non-200 response body kept by RestError.
+const MaxErrorBodySize = 4 << 10
+
+// RestError is returned for non-200 responses.
+// Use errors.Is with the status sentinel errors, such as ErrRateLimited, to classify it.
 type RestError struct {
-	Response      *http.Response
+	// The response, including its status code and headers. Its body has already been consumed.
+	Response *http.Response
+
+	// The decoded error, or nil if the body is not a JSON error response.
 	ErrorResponse *ErrorResponse
+
+	// Up to MaxErrorBodySize bytes of the decompressed body, or of the raw body if it could not be decompressed.
+	Body []byte
 }
 
 func (e *RestError) Error() string {
-	return fmt.Sprintf("http: status code: %d %s %s", e.Response.StatusCode, http.StatusText(e.Response.StatusCode), e.ErrorResponse.Message)
+	status := e.statusCode()
+	message := fmt.Sprintf("http: status code: %d %s", status, http.StatusText(status))
+
+	if e.ErrorResponse != nil && len(e.ErrorResponse.Message) > 0 {
+		message += " " + e.ErrorResponse.Message
+	}
+
+	return message
+}
+
+// Is reports whether the response status code corresponds to the target sentinel error.
+func (e *RestError) Is(target error) bool {
+	status := e.statusCode()
+
+	switch target {
+	case ErrBadRequest:
+		return status == http.StatusBadRequest
+	case ErrUnauthorized:
+		return status == http.StatusUnauthorized
+	case ErrForbidden:
+		return status == http.StatusForbidden
+	case ErrNotFound:
+		return status == http.StatusNotFound
+	case ErrRateLimited:
+		return status == http.StatusTooManyRequests
+	case ErrServerError:
+		return status >= http.StatusInternalServerError && status <= 599
+	}
+
+	return false
+}
+
+func (e *RestError) statusCode() int {
+	if e.Response == nil {
+		return 0
+	}
+
+	return e.Response.StatusCode
+}
+
+// DecodeError is returned when a response body cannot be decoded.
+type DecodeError struct {
+	// The endpoint which returned the response.
+	Endpoint Endpoint
+
+	Err error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("%s: %s: %s", ErrDecode, e.Endpoint, e.Err)
+}
+
+// Unwrap returns the underlying decoding error.
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the target is ErrDecode.
+func (e *DecodeError) Is(target error) bool {
+	return target == ErrDecode
+}
+
+// TokenError is returned by CredentialedClient when a developer token cannot be obtained.
+type TokenError struct {
+	Err error
+}
+
+func (e *TokenError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrToken, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *TokenError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the target is ErrToken.
+func (e *TokenError) Is(target error) bool {
+	return target == ErrToken
+}
+
+// DataSetUnavailableError reports requested data sets which a WeatherResponse does not provide.
+type DataSetUnavailableError struct {
+	DataSets DataSets
+}
+
+func (e *DataSetUnavailableError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrDataSetUnavailable, e.DataSets)
+}
+
+// Is reports whether the target is ErrDataSetUnavailable.
+func (e *DataSetUnavailableError) Is(target error) bool {
+	return target == ErrDataSetUnavailable
+}
+
+// IsRetryable reports whether the request which failed with err may succeed if sent again:
+// rate limited and server errors, and transport errors not caused by the request context.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	restError := &RestError{}
+	if errors.As(err, &restError) {
+		return errors.Is(restError, ErrRateLimited) || errors.Is(restError, ErrServerError)
+	}
+
+	urlError := &url.Error{}
+	return errors.As(err, &urlError)
+}
+
+// RetryAfter returns the delay requested by the Retry-After header of a RestError.
+func RetryAfter(err error) (time.Duration, bool) {
+	restError := &RestError{}
+	if !errors.As(err, &restError) || restError.Response == nil {
+		return 0, false
+	}
+
+	return parseRetryAfter(restError.Response.Header.Get("Retry-After"), time.Now())
+}
+
+// Unavailable returns a DataSetUnavailableError listing the requested data sets which are
+// missing from the response or marked as temporarily unavailable, or nil if all are present.
+func (r *WeatherResponse) Unavailable(requested DataSets) error {
+	unavailable := DataSets{}
+
+	for _, dataSet := range requested {
+		block, _ := r.dataSet(dataSet)
+		if block == nil || temporarilyUnavailable(block) {
+			unavailable = append(unavailable, dataSet)
+		}
+	}
+
+	if len(unavailable) < 1 {
+		return nil
+	}
+
+	return &DataSetUnavailableError{DataSets: unavailable}
+}
+
+func temporarilyUnavailable(block interface{}) bool {
+	if product, ok := block.(interface{ metadata() Metadata }); ok {
+		return product.metadata().TemporarilyUnavailable
+	}
+
+	return false
 }
@@ -0,0 +1,210 @@
+package weatherkit
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRestErrorIs(t *testing.T) {
+	sentinels := []error{ErrBadRequest, ErrUnauthorized, ErrForbidden, ErrNotFound, ErrRateLimited, ErrServerError}
+
+	tests := map[int]error{
+		http.StatusBadRequest:          ErrBadRequest,
+		http.StatusUnauthorized:        ErrUnauthorized,
+		http.StatusForbidden:           ErrForbidden,
+		http.StatusNotFound:            ErrNotFound,
+		http.StatusTooManyRequests:     ErrRateLimited,
+		http.StatusInternalServerError: ErrServerError,
+		http.StatusServiceUnavailable:  ErrServerError,
+		http.StatusTeapot:              nil,
+	}
+
+	for status, want := range tests {
+		err := fmt.Errorf("wrapped: %w", &RestError{
+			Response:      &http.Response{StatusCode: status},
+			ErrorResponse: &ErrorResponse{},
+		})
+
+		for _, sentinel := range sentinels {
+			if have := errors.Is(err, sentinel); have != (sentinel == want) {
+				t.Errorf("status %d is %v want: %t, have: %t", status, sentinel, sentinel == want, have)
+			}
+		}
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{&RestError{Response: &http.Response{StatusCode: http.StatusTooManyRequests}}, true},
+		{&RestError{Response: &http.Response{StatusCode: http.StatusBadGateway}}, true},
+		{&RestError{Response: &http.Response{StatusCode: http.StatusUnauthorized}}, false},
+		{&url.Error{Op: "Get", URL: "https://weatherkit.apple.com", Err: errors.New("connection reset")}, true},
+		{&url.Error{Op: "Get", URL: "https://weatherkit.apple.com", Err: context.Canceled}, false},
+		{context.DeadlineExceeded, false},
+		{&DecodeError{Err: errors.New("unexpected EOF")}, false},
+		{ErrQuotaExhausted, false},
+	}
+
+	for _, test := range tests {
+		if have := IsRetryable(test.err); have != test.want {
+			t.Errorf("%v want: %t, have: %t", test.err, test.want, have)
+		}
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	header := http.Header{}
+	header.Set("Retry-After", "30")
+
+	err := fmt.Errorf("wrapped: %w", &RestError{
+		Response: &http.Response{StatusCode: http.StatusTooManyRequests, Header: header},
+	})
+
+	have, ok := RetryAfter(err)
+	if !ok || have != 30*time.Second {
+		t.Errorf("want: %s, have: %s %t", 30*time.Second, have, ok)
+	}
+
+	if _, ok := RetryAfter(errors.New("other")); ok {
+		t.Errorf("expected no delay for other errors")
+	}
+}
+
+func TestDecodeErrorWrapping(t *testing.T) {
+	server := getMockServer([]byte("{not json"), http.StatusOK)
+	defer server.Close()
+
+	client := Client{BaseURL: server.URL}
+
+	_, err := client.Weather(context.TODO(), "", WeatherRequest{})
+	if !errors.Is(err, ErrDecode) {
+		t.Fatalf("expected %v, got: %v", ErrDecode, err)
+	}
+
+	decodeError := &DecodeError{}
+	if !errors.As(err, &decodeError) || decodeError.Endpoint != EndpointWeather {
+		t.Errorf("expected a DecodeError for the weather endpoint, have: %#v", err)
+	}
+}
+
+func TestTokenErrorWrapping(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("expected no request to be sent")
+	}))
+	defer server.Close()
+
+	for _, opts := range [][]CredentialedClientOption{
+		{WithBaseURL(server.URL)},
+		{WithBaseURL(server.URL), WithoutCache()},
+	} {
+		client := NewCredentialedClient(Credentials{}, opts...)
+
+		_, err := client.Weather(context.TODO(), WeatherRequest{})
+		if !errors.Is(err, ErrToken) {
+			t.Errorf("expected %v, got: %v", ErrToken, err)
+		}
+	}
+}
+
+func TestWeatherResponseUnavailable(t *testing.T) {
+	response := WeatherResponse{
+		CurrentWeather: &CurrentWeather{},
+		ForcastDaily: &DailyForecast{
+			ProductData: ProductData{Metadata: Metadata{TemporarilyUnavailable: true}},
+		},
+	}
+
+	if err := response.Unavailable(DataSets{DataSetCurrentWeather}); err != nil {
+		t.Errorf("expected no error, got: %v", err)
+	}
+
+	err := response.Unavailable(DataSets{DataSetCurrentWeather, DataSetForecastDaily, DataSetForecastHourly})
+	if !errors.Is(err, ErrDataSetUnavailable) {
+		t.Fatalf("expected %v, got: %v", ErrDataSetUnavailable, err)
+	}
+
+	unavailable := &DataSetUnavailableError{}
+	if !errors.As(err, &unavailable) || unavailable.DataSets.String() != "forecastDaily,forecastHourly" {
+		t.Errorf("want: %s, have: %v", "forecastDaily,forecastHourly", err)
+	}
+}
+
+func TestRestErrorBodies(t *testing.T) {
+	compressed := &bytes.Buffer{}
+	writer := gzip.NewWriter(compressed)
+	_, _ = writer.Write([]byte(`{"status":503,"message":"maintenance"}`))
+	_ = writer.Close()
+
+	tests := []struct {
+		name     string
+		encoding string
+		body     []byte
+		wantBody []byte
+		message  string
+	}{
+		{"html", "", []byte("<html><body>Bad Gateway</body></html>"), []byte("<html><body>Bad Gateway</body></html>"), ""},
+		{"empty", "", nil, []byte{}, ""},
+		{"oversized", "", bytes.Repeat([]byte("x"), MaxErrorBodySize*2), bytes.Repeat([]byte("x"), MaxErrorBodySize), ""},
+		{"gzip", "gzip", compressed.Bytes(), []byte(`{"status":503,"message":"maintenance"}`), "maintenance"},
+		{"corrupt gzip", "gzip", []byte("not gzip"), []byte("not gzip"), ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if len(test.encoding) > 0 {
+					w.Header().Set("Content-Encoding", test.encoding)
+				}
+				w.Header().Set("X-Request-Id", "abc")
+				w.WriteHeader(http.StatusServiceUnavailable)
+				_, _ = w.Write(test.body)
+			}))
+			defer server.Close()
+
+			client := Client{BaseURL: server.URL}
+
+			_, err := client.Weather(context.TODO(), "", WeatherRequest{})
+
+			restError := &RestError{}
+			if !errors.As(err, &restError) {
+				t.Fatalf("expected a RestError, got: %v", err)
+			}
+
+			if restError.Response.StatusCode != http.StatusServiceUnavailable || restError.Response.Header.Get("X-Request-Id") != "abc" {
+				t.Errorf("expected the status and headers to be kept, have: %d %v", restError.Response.StatusCode, restError.Response.Header)
+			}
+
+			if !bytes.Equal(restError.Body, test.wantBody) {
+				t.Errorf("want body: %q, have: %q", test.wantBody, restError.Body)
+			}
+
+			if len(test.message) < 1 {
+				if restError.ErrorResponse != nil {
+					t.Errorf("expected no error response, have: %+v", restError.ErrorResponse)
+				}
+				return
+			}
+
+			if restError.ErrorResponse == nil || restError.ErrorResponse.Message != test.message {
+				t.Errorf("want message: %s, have: %+v", test.message, restError.ErrorResponse)
+			}
+
+			if !strings.HasSuffix(err.Error(), test.message) {
+				t.Errorf("expected the message in %q", err.Error())
+			}
+		})
+	}
+}
@@ -3,25 +3,20 @@ package main
 import (
 	"context"
 	"fmt"
-	"os"
 
 	"github.com/shawntoffel/go-weatherkit"
 )
 
 // print the current temp in new york
 func main() {
-	privateKeyBytes, err := os.ReadFile("/path/to/AuthKey_ABCDE12345.p8")
+	// Reads WEATHERKIT_KEY_ID, WEATHERKIT_TEAM_ID, WEATHERKIT_SERVICE_ID and WEATHERKIT_PRIVATE_KEY_PATH.
+	credentials, err := weatherkit.CredentialsFromEnv()
 	if err != nil {
-		fmt.Println("failed to load private key", err.Error())
+		fmt.Println("failed to load credentials", err.Error())
 		return
 	}
 
-	client := weatherkit.NewCredentialedClient(weatherkit.Credentials{
-		KeyID:      "key ID",
-		TeamID:     "team ID",
-		ServiceID:  "service ID",
-		PrivateKey: privateKeyBytes,
-	})
+	client := weatherkit.NewCredentialedClient(credentials)
 
 	ctx := context.Background()
 
@@ -0,0 +1,312 @@
+package weatherkit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// UnknownFields lists the JSON fields captured into the Extras of v and of the values nested in it,
+// as paths such as "forecastDaily.metadata.newField". An empty list means the response is fully modeled.
+func UnknownFields(v interface{}) []string {
+	fields := []string{}
+	collectUnknownFields(reflect.ValueOf(v), "", &fields)
+	sort.Strings(fields)
+
+	return fields
+}
+
+var extrasType = reflect.TypeOf(map[string]json.RawMessage{})
+
+func collectUnknownFields(v reflect.Value, path string, fields *[]string) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			collectUnknownFields(v.Elem(), path, fields)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			collectUnknownFields(v.Index(i), fmt.Sprintf("%s[%d]", path, i), fields)
+		}
+	case reflect.Struct:
+		t := v.Type()
+
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if len(field.PkgPath) > 0 {
+				continue
+			}
+
+			if field.Name == "Extras" && field.Type == extrasType {
+				for key := range v.Field(i).Interface().(map[string]json.RawMessage) {
+					*fields = append(*fields, joinFieldPath(path, key))
+				}
+				continue
+			}
+
+			name, embedded := jsonFieldName(field)
+			switch {
+			case embedded:
+				collectUnknownFields(v.Field(i), path, fields)
+			case len(name) > 0:
+				collectUnknownFields(v.Field(i), joinFieldPath(path, name), fields)
+			}
+		}
+	}
+}
+
+func joinFieldPath(path string, name string) string {
+	if len(path) < 1 {
+		return name
+	}
+
+	return path + "." + name
+}
+
+// jsonFieldName returns the JSON key of the struct field, or reports that the field is an
+// embedded struct whose fields are promoted into the enclosing object.
+func jsonFieldName(field reflect.StructField) (string, bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+
+	name := strings.Split(tag, ",")[0]
+	if len(name) > 0 {
+		return name, false
+	}
+
+	t := field.Type
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if field.Anonymous && t.Kind() == reflect.Struct {
+		return "", true
+	}
+
+	return field.Name, false
+}
+
+// knownFields holds the JSON keys decoded by each struct type, both as named and lower cased,
+// matching the case-insensitive decoding of encoding/json.
+var knownFields sync.Map
+
+func knownFieldsOf(t reflect.Type) map[string]bool {
+	if known, ok := knownFields.Load(t); ok {
+		return known.(map[string]bool)
+	}
+
+	known := map[string]bool{}
+	addKnownFields(t, known)
+	knownFields.Store(t, known)
+
+	return known
+}
+
+func addKnownFields(t reflect.Type, known map[string]bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if len(field.PkgPath) > 0 && !field.Anonymous {
+			continue
+		}
+
+		name, embedded := jsonFieldName(field)
+		switch {
+		case embedded:
+			ft := field.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			addKnownFields(ft, known)
+		case len(name) > 0:
+			known[name] = true
+			known[strings.ToLower(name)] = true
+		}
+	}
+}
+
+// unmarshalWithExtras decodes data into v, a pointer to a struct, and returns the keys of data
+// not decoded into any of its fields. It returns nil if there are none.
+// The keys are only collected into a map when data has any unknown key.
+func unmarshalWithExtras(data []byte, v interface{}) (map[string]json.RawMessage, error) {
+	err := json.Unmarshal(data, v)
+	if err != nil {
+		return nil, err
+	}
+
+	t := reflect.TypeOf(v).Elem()
+	if !hasUnknownKey(data, knownFieldsOf(t)) {
+		return nil, nil
+	}
+
+	fields := map[string]json.RawMessage{}
+
+	err = json.Unmarshal(data, &fields)
+	if err != nil {
+		return nil, err
+	}
+
+	return unknownKeys(fields, t), nil
+}
+
+// hasUnknownKey reports whether the JSON object in data has a key missing from known. It only scans
+// for the keys of the object, skipping over their values, so data must already be known to be valid.
+func hasUnknownKey(data []byte, known map[string]bool) bool {
+	depth := 0
+	expectKey := false
+
+	for i := 0; i < len(data); i++ {
+		switch data[i] {
+		case '{':
+			depth++
+			expectKey = depth == 1
+		case '[':
+			depth++
+		case '}', ']':
+			depth--
+		case ',':
+			expectKey = depth == 1
+		case '"':
+			end := stringEnd(data, i)
+			if expectKey && !isKnownKey(data[i:end+1], known) {
+				return true
+			}
+
+			expectKey = false
+			i = end
+		}
+	}
+
+	return false
+}
+
+// stringEnd returns the index of the quote closing the JSON string starting at start.
+func stringEnd(data []byte, start int) int {
+	for i := start + 1; i < len(data); i++ {
+		quote := bytes.IndexByte(data[i:], '"')
+		if quote < 0 {
+			break
+		}
+		i += quote
+
+		// The quote is escaped if an odd number of backslashes precede it.
+		escapes := 0
+		for j := i - 1; j > start && data[j] == '\\'; j-- {
+			escapes++
+		}
+
+		if escapes%2 == 0 {
+			return i
+		}
+	}
+
+	return len(data) - 1
+}
+
+// isKnownKey reports whether the quoted JSON key is in known, matching its case-insensitive lookup.
+func isKnownKey(quoted []byte, known map[string]bool) bool {
+	raw := quoted[1 : len(quoted)-1]
+	if bytes.IndexByte(raw, '\\') < 0 {
+		return known[string(raw)] || known[strings.ToLower(string(raw))]
+	}
+
+	key := ""
+	if json.Unmarshal(quoted, &key) != nil {
+		return false
+	}
+
+	return known[key] || known[strings.ToLower(key)]
+}
+
+// unknownKeys removes the keys decoded by the struct type t from fields, returning nil if none are left.
+func unknownKeys(fields map[string]json.RawMessage, t reflect.Type) map[string]json.RawMessage {
+	known := knownFieldsOf(t)
+
+	for key := range fields {
+		if known[strings.ToLower(key)] {
+			delete(fields, key)
+		}
+	}
+
+	if len(fields) < 1 {
+		return nil
+	}
+
+	return fields
+}
+
+// marshalWithExtras encodes v, a struct, followed by the extras not already encoded from its fields.
+func marshalWithExtras(v interface{}, extras map[string]json.RawMessage) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil || len(extras) < 1 {
+		return data, err
+	}
+
+	return appendObjectFields(data, unknownKeys(copyExtras(extras), reflect.TypeOf(v)))
+}
+
+// appendObjectFields appends the fields, sorted by key, to the encoded JSON object.
+func appendObjectFields(object []byte, fields map[string]json.RawMessage) ([]byte, error) {
+	if len(fields) < 1 {
+		return object, nil
+	}
+
+	object = bytes.TrimSpace(object)
+	if len(object) < 2 || object[len(object)-1] != '}' {
+		return nil, fmt.Errorf("cannot add fields to a JSON value which is not an object: %s", object)
+	}
+
+	buf := bytes.NewBuffer(append([]byte{}, object[:len(object)-1]...))
+	needsComma := len(bytes.TrimSpace(object[1:len(object)-1])) > 0
+
+	for _, key := range sortedKeys(fields) {
+		name, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+
+		if needsComma {
+			buf.WriteByte(',')
+		}
+		needsComma = true
+
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(fields[key])
+	}
+
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+func copyExtras(extras map[string]json.RawMessage) map[string]json.RawMessage {
+	copied := make(map[string]json.RawMessage, len(extras))
+	for key, value := range extras {
+		copied[key] = value
+	}
+
+	return copied
+}
+
+// reportUnknownFields records the fields of the result not modeled by the response types into the
+// captured RawResponse, if any, and logs them as a warning.
+func (d *Client) reportUnknownFields(ctx context.Context, call *Call, raw *RawResponse) {
+	fields := UnknownFields(call.Result)
+
+	if raw != nil {
+		raw.UnknownFields = fields
+	}
+
+	if d.Logger == nil || len(fields) < 1 {
+		return
+	}
+
+	d.Logger.WarnContext(ctx, "unknown response fields", "endpoint", call.Endpoint, "fields", fields)
+}
@@ -0,0 +1,259 @@
+package weatherkit
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+const weatherWithUnknownFields = `{
+	"currentWeather": {
+		"name": "CurrentWeather",
+		"metadata": {"version": 1, "latitude": 1, "longitude": 2, "sourceType": "modeled"},
+		"temperature": 20,
+		"cloudCoverLowAltPct": 0.1,
+		"precipitationIntensity": 0
+	},
+	"forecastDaily": {
+		"name": "DailyForecast",
+		"metadata": {"version": 1, "latitude": 1, "longitude": 2},
+		"days": [],
+		"learnMoreURL": "https://example.com"
+	},
+	"forecastHourly": {
+		"name": "HourlyForecast",
+		"metadata": {"version": 1, "latitude": 1, "longitude": 2},
+		"hours": [],
+		"summary": {"nested": [1, 2]}
+	},
+	"forecastNextHour": {
+		"name": "NextHourForecast",
+		"metadata": {"version": 1, "latitude": 1, "longitude": 2},
+		"minutes": [],
+		"confidence": "high"
+	},
+	"weatherAlerts": {
+		"alerts": [{"id": "alert", "importance": "normal"}]
+	}
+}`
+
+func TestUnknownFieldsRoundTrip(t *testing.T) {
+	response := WeatherResponse{}
+
+	err := json.Unmarshal([]byte(weatherWithUnknownFields), &response)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if response.CurrentWeather.Temperature != 20 || response.CurrentWeather.Metadata.Latitude != 1 {
+		t.Errorf("expected known fields to be decoded, have: %+v", response.CurrentWeather)
+	}
+
+	if have := string(response.CurrentWeather.Extras["cloudCoverLowAltPct"]); have != "0.1" {
+		t.Errorf("want: %s, have: %s", "0.1", have)
+	}
+
+	if have := string(response.CurrentWeather.Metadata.Extras["sourceType"]); have != `"modeled"` {
+		t.Errorf("want: %s, have: %s", `"modeled"`, have)
+	}
+
+	want := []string{
+		"currentWeather.cloudCoverLowAltPct",
+		"currentWeather.metadata.sourceType",
+		"forecastDaily.learnMoreURL",
+		"forecastHourly.summary",
+		"forecastNextHour.confidence",
+		"weatherAlerts.alerts[0].importance",
+	}
+
+	if have := UnknownFields(&response); !reflect.DeepEqual(have, want) {
+		t.Errorf("want: %v, have: %v", want, have)
+	}
+
+	encoded, err := json.Marshal(response)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	decoded := WeatherResponse{}
+
+	err = json.Unmarshal(encoded, &decoded)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if have := UnknownFields(&decoded); !reflect.DeepEqual(have, want) {
+		t.Errorf("want: %v, have: %v", want, have)
+	}
+
+	reencoded, err := json.Marshal(decoded)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if string(reencoded) != string(encoded) {
+		t.Errorf("expected the response to round trip, want: %s, have: %s", encoded, reencoded)
+	}
+}
+
+func TestUnknownFieldsWeatherAlertResponse(t *testing.T) {
+	data := []byte(`{"id": "alert", "area": {"type": "FeatureCollection", "features": []}, "eventText": [{"language": "en", "text": "Storm"}], "messages": ["a"]}`)
+
+	response := WeatherAlertResponse{}
+
+	err := json.Unmarshal(data, &response)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if response.ID != "alert" || len(response.EventText) != 1 {
+		t.Errorf("expected both parts of the response to be decoded, have: %+v", response)
+	}
+
+	want := []string{"messages"}
+	if have := UnknownFields(response); !reflect.DeepEqual(have, want) {
+		t.Errorf("want: %v, have: %v", want, have)
+	}
+
+	encoded, err := json.Marshal(response)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	assertJsonEqual(t, data, encoded)
+}
+
+func TestFixturesHaveNoUnknownFields(t *testing.T) {
+	fixtures := []string{
+		"testdata/current_weather.json",
+		"testdata/forecast_daily.json",
+		"testdata/forecast_hourly.json",
+		"testdata/next_hour_forecast.json",
+		"testdata/historical_comparisons.json",
+		"testdata/weather_changes.json",
+		"testdata/full_weather.json",
+	}
+
+	for _, fixture := range fixtures {
+		data, err := ioutil.ReadFile(fixture)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		response := WeatherResponse{}
+
+		err = json.Unmarshal(data, &response)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		if fields := UnknownFields(&response); len(fields) > 0 {
+			t.Errorf("%s: unknown fields: %v", fixture, fields)
+		}
+	}
+}
+
+func TestStrictLogsUnknownFields(t *testing.T) {
+	server := getMockServer([]byte(weatherWithUnknownFields), http.StatusOK)
+	defer server.Close()
+
+	logger := &testLogger{}
+	client := Client{BaseURL: server.URL, Logger: logger}
+
+	_, err := client.Weather(context.TODO(), "", WeatherRequest{})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if _, ok := logger.find("unknown response fields"); ok {
+		t.Fatalf("expected no warning without strict mode")
+	}
+
+	client.Strict = true
+
+	_, err = client.Weather(context.TODO(), "", WeatherRequest{})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	record, ok := logger.find("unknown response fields")
+	if !ok || record.level != "warn" {
+		t.Fatalf("expected a warning, have: %s", logger)
+	}
+
+	if fields, _ := record.value("fields"); len(fields.([]string)) != 6 {
+		t.Errorf("want: %d fields, have: %v", 6, fields)
+	}
+}
+
+func TestStrictWithoutLogger(t *testing.T) {
+	server := getMockServer([]byte(weatherWithUnknownFields), http.StatusOK)
+	defer server.Close()
+
+	client := Client{BaseURL: server.URL, Strict: true}
+
+	raw := RawResponse{}
+
+	_, err := client.Weather(CaptureRawResponse(context.TODO(), &raw), "", WeatherRequest{})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(raw.UnknownFields) != 6 {
+		t.Errorf("want: %d fields, have: %v", 6, raw.UnknownFields)
+	}
+
+	client.Strict = false
+	raw = RawResponse{}
+
+	_, err = client.Weather(CaptureRawResponse(context.TODO(), &raw), "", WeatherRequest{})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if raw.UnknownFields != nil {
+		t.Errorf("expected no fields without strict mode, have: %v", raw.UnknownFields)
+	}
+}
+
+func BenchmarkUnmarshalWithExtras(b *testing.B) {
+	data, err := ioutil.ReadFile("testdata/full_weather.json")
+	if err != nil {
+		b.Fatal(err.Error())
+	}
+
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		err := json.Unmarshal(data, &WeatherResponse{})
+		if err != nil {
+			b.Fatal(err.Error())
+		}
+	}
+}
+
+func TestHasUnknownKey(t *testing.T) {
+	known := map[string]bool{"name": true, "nested": true}
+
+	tests := map[string]bool{
+		`{"name":"a"}`:                        false,
+		`{"NAME":"a"}`:                        false,
+		`{"\u006eame":"a"}`:                   false,
+		`{"name":"\"other\":1","nested":{}}`:  false,
+		`{"nested":{"other":1},"name":"\\"}`:  false,
+		`{"nested":[{"other":1}],"name":"a"}`: false,
+		`{"name":"a","other":1}`:              true,
+		`{"nested":{"a":[1,{"b":2}]},"x":1}`:  true,
+		`{"name":"a\\","other":1}`:            true,
+		`null`:                                false,
+	}
+
+	for data, want := range tests {
+		if have := hasUnknownKey([]byte(data), known); have != want {
+			t.Errorf("%s want: %t, have: %t", data, want, have)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package weatherkit
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	fileCacheExtension  = ".cache"
+	fileCacheTempPrefix = ".tmp-"
+
+	// Temporary files older than this are assumed to be left behind by a crashed process.
+	fileCacheStaleTempAge = time.Hour
+)
+
+// NewFileCache creates a Cache which stores one file per key in dir, creating dir if needed.
+// Once the files exceed maxBytes in total, expired and then least recently used files are removed.
+// A maxBytes of zero or less does not bound the size of the cache.
+func NewFileCache(dir string, maxBytes int64) (*FileCache, error) {
+	err := os.MkdirAll(dir, 0o700)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FileCache{
+		dir:      dir,
+		maxBytes: maxBytes,
+	}, nil
+}
+
+// FileCache is a persistent Cache backed by the file system.
+// Files are written atomically, so a directory may be shared by multiple processes.
+// File system errors are treated as cache misses.
+// Construct with NewFileCache.
+type FileCache struct {
+	dir      string
+	maxBytes int64
+	mu       sync.Mutex
+}
+
+// Get implements Cache.
+func (c *FileCache) Get(key string) ([]byte, bool) {
+	path := c.path(key)
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+
+	value, expires, ok := decodeFileCacheEntry(data)
+	if !ok {
+		_ = os.Remove(path)
+		return nil, false
+	}
+
+	now := time.Now()
+	if !expires.After(now) {
+		_ = os.Remove(path)
+		return nil, false
+	}
+
+	// The modification time tracks recent use for eviction.
+	_ = os.Chtimes(path, now, now)
+
+	return value, true
+}
+
+// Set implements Cache.
+func (c *FileCache) Set(key string, value []byte, expires time.Time) {
+	if !expires.After(time.Now()) {
+		return
+	}
+
+	file, err := ioutil.TempFile(c.dir, fileCacheTempPrefix)
+	if err != nil {
+		return
+	}
+
+	_, err = file.Write(encodeFileCacheEntry(value, expires))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err == nil {
+		err = os.Rename(file.Name(), c.path(key))
+	}
+
+	if err != nil {
+		_ = os.Remove(file.Name())
+		return
+	}
+
+	if c.maxBytes > 0 {
+		c.evict()
+	}
+}
+
+// evict removes expired entries, then the least recently used ones, until the cache fits within maxBytes.
+func (c *FileCache) evict() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return
+	}
+
+	type file struct {
+		path    string
+		size    int64
+		modTime time.Time
+	}
+
+	now := time.Now()
+	files := []file{}
+	total := int64(0)
+
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || info.IsDir() {
+			continue
+		}
+
+		path := filepath.Join(c.dir, entry.Name())
+
+		if strings.HasPrefix(entry.Name(), fileCacheTempPrefix) {
+			if now.Sub(info.ModTime()) > fileCacheStaleTempAge {
+				_ = os.Remove(path)
+			}
+			continue
+		}
+
+		if !strings.HasSuffix(entry.Name(), fileCacheExtension) {
+			continue
+		}
+
+		if c.expired(path, now) {
+			_ = os.Remove(path)
+			continue
+		}
+
+		files = append(files, file{path: path, size: info.Size(), modTime: info.ModTime()})
+		total += info.Size()
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].modTime.Before(files[j].modTime)
+	})
+
+	for i := 0; total > c.maxBytes && i < len(files); i++ {
+		if os.Remove(files[i].path) == nil {
+			total -= files[i].size
+		}
+	}
+}
+
+func (c *FileCache) expired(path string, now time.Time) bool {
+	file, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	// Only the header line holding the expiration time is needed.
+	header := make([]byte, 64)
+	n, _ := io.ReadFull(file, header)
+
+	_, expires, ok := decodeFileCacheEntry(header[:n])
+	return !ok || !expires.After(now)
+}
+
+func (c *FileCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+fileCacheExtension)
+}
+
+// encodeFileCacheEntry prefixes the value with its expiration time on a line of its own.
+func encodeFileCacheEntry(value []byte, expires time.Time) []byte {
+	header := expires.UTC().Format(time.RFC3339Nano) + "\n"
+	return append([]byte(header), value...)
+}
+
+func decodeFileCacheEntry(data []byte) ([]byte, time.Time, bool) {
+	i := bytes.IndexByte(data, '\n')
+	if i < 0 {
+		return nil, time.Time{}, false
+	}
+
+	expires, err := time.Parse(time.RFC3339Nano, string(data[:i]))
+	if err != nil {
+		return nil, time.Time{}, false
+	}
+
+	return data[i+1:], expires, true
+}
@@ -0,0 +1,193 @@
+package weatherkit
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFileCacheGetSet(t *testing.T) {
+	cache, err := NewFileCache(filepath.Join(t.TempDir(), "cache"), 0)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if _, ok := cache.Get("missing"); ok {
+		t.Errorf("expected missing key not to be cached")
+	}
+
+	cache.Set("key", []byte(`{"a":1}`), time.Now().Add(time.Hour))
+
+	value, ok := cache.Get("key")
+	if !ok || string(value) != `{"a":1}` {
+		t.Errorf("want: %s, have: %s", `{"a":1}`, value)
+	}
+
+	cache.Set("expired", []byte(`{}`), time.Now().Add(-time.Second))
+	if _, ok := cache.Get("expired"); ok {
+		t.Errorf("expected expired entry not to be cached")
+	}
+}
+
+func TestFileCacheExpires(t *testing.T) {
+	dir := t.TempDir()
+
+	cache, err := NewFileCache(dir, 0)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	cache.Set("key", []byte(`{}`), time.Now().Add(10*time.Millisecond))
+	time.Sleep(20 * time.Millisecond)
+
+	if _, ok := cache.Get("key"); ok {
+		t.Errorf("expected entry to expire")
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 0 {
+		t.Errorf("expected expired file to be removed, have: %d files", len(entries))
+	}
+}
+
+func TestFileCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	dir := t.TempDir()
+	value := []byte(strings.Repeat("x", 100))
+
+	// Each entry takes about 130 bytes, so only three fit.
+	cache, err := NewFileCache(dir, 450)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	expires := time.Now().Add(time.Hour)
+	for i, key := range []string{"a", "b", "c"} {
+		cache.Set(key, value, expires)
+
+		// Ensure distinct modification times.
+		past := time.Now().Add(-time.Duration(3-i) * time.Minute)
+		_ = os.Chtimes(cache.path(key), past, past)
+	}
+
+	cache.Get("a")
+	cache.Set("d", value, expires)
+
+	if _, ok := cache.Get("b"); ok {
+		t.Errorf("expected b to be evicted")
+	}
+
+	for _, key := range []string{"a", "d"} {
+		if _, ok := cache.Get(key); !ok {
+			t.Errorf("expected %s to be cached", key)
+		}
+	}
+}
+
+func TestFileCacheConcurrentWriters(t *testing.T) {
+	dir := t.TempDir()
+	expires := time.Now().Add(time.Hour)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			// Separate instances stand in for separate processes sharing a directory.
+			cache, err := NewFileCache(dir, 0)
+			if err != nil {
+				t.Error(err.Error())
+				return
+			}
+
+			for j := 0; j < 20; j++ {
+				cache.Set("shared", []byte(fmt.Sprintf(`{"writer":%d}`, i)), expires)
+
+				value, ok := cache.Get("shared")
+				if !ok || !strings.HasPrefix(string(value), `{"writer":`) {
+					t.Errorf("expected a complete entry, have: %s", value)
+				}
+			}
+		}(i)
+	}
+
+	wg.Wait()
+}
+
+func TestFileCacheServesRepeatedRuns(t *testing.T) {
+	var calls int32
+
+	weatherServer, requested := getMockDataSetServer(t, time.Now().Add(time.Hour))
+	defer weatherServer.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/api/v1/availability/"):
+			fmt.Fprintln(w, `["currentWeather","forecastDaily"]`)
+		case strings.HasPrefix(r.URL.Path, "/attribution/"):
+			fmt.Fprintln(w, `{"serviceName":"Weather"}`)
+		default:
+			http.Redirect(w, r, weatherServer.URL+r.URL.RequestURI(), http.StatusTemporaryRedirect)
+		}
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+
+	for run := 0; run < 2; run++ {
+		cache, err := NewFileCache(dir, 0)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		client := Client{BaseURL: server.URL, Cache: cache}
+
+		weather, err := client.Weather(context.TODO(), "", WeatherRequest{
+			Language: "en",
+			DataSets: DataSets{DataSetForecastDaily},
+		})
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		if weather.ForcastDaily == nil || len(weather.ForcastDaily.Days) < 1 {
+			t.Errorf("expected daily forecast, have: %+v", weather)
+		}
+
+		availability, err := client.Availability(context.TODO(), "", AvailabilityRequest{Latitude: 1, Longitude: 2})
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		if len(*availability) != 2 {
+			t.Errorf("want: %d data sets, have: %d", 2, len(*availability))
+		}
+
+		attribution, err := client.Attribution(context.TODO(), AttributionRequest{Language: "en"})
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		if attribution.ServiceName != "Weather" {
+			t.Errorf("want: %s, have: %s", "Weather", attribution.ServiceName)
+		}
+	}
+
+	if calls != 3 {
+		t.Errorf("want: %d calls, have: %d", 3, calls)
+	}
+
+	if len(*requested) != 1 {
+		t.Errorf("want: %d weather requests, have: %d", 1, len(*requested))
+	}
+}
@@ -0,0 +1,167 @@
+package weatherkit
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// GeoJSON object types.
+const (
+	geoJSONFeatureCollection = "FeatureCollection"
+	geoJSONFeature           = "Feature"
+)
+
+// GeometryType is the type of a GeoJSON geometry.
+type GeometryType string
+
+const (
+	// A single polygon, possibly with holes.
+	GeometryTypePolygon GeometryType = "Polygon"
+
+	// A collection of polygons.
+	GeometryTypeMultiPolygon GeometryType = "MultiPolygon"
+)
+
+// Position is a GeoJSON position: the longitude followed by the latitude, in degrees.
+type Position [2]float64
+
+// Longitude returns the longitude of the position, in degrees.
+func (p Position) Longitude() float64 {
+	return p[0]
+}
+
+// Latitude returns the latitude of the position, in degrees.
+func (p Position) Latitude() float64 {
+	return p[1]
+}
+
+// Polygon is a GeoJSON polygon: the exterior ring followed by any holes.
+// Each ring is closed, its first and last positions being the same.
+type Polygon [][]Position
+
+// MultiPolygon is a GeoJSON multi polygon.
+type MultiPolygon []Polygon
+
+// Geometry is a GeoJSON geometry.
+type Geometry struct {
+	// (Required) The type of the geometry.
+	Type GeometryType
+
+	// The coordinates of a GeometryTypePolygon geometry.
+	Polygon Polygon
+
+	// The coordinates of a GeometryTypeMultiPolygon geometry.
+	MultiPolygon MultiPolygon
+
+	// The undecoded coordinates of any other type of geometry.
+	Coordinates json.RawMessage
+}
+
+type geoJSONGeometry struct {
+	Type        GeometryType    `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates"`
+}
+
+// UnmarshalJSON decodes a GeoJSON geometry.
+func (g *Geometry) UnmarshalJSON(data []byte) error {
+	geometry := geoJSONGeometry{}
+
+	err := json.Unmarshal(data, &geometry)
+	if err != nil {
+		return err
+	}
+
+	*g = Geometry{Type: geometry.Type}
+
+	switch geometry.Type {
+	case GeometryTypePolygon:
+		return json.Unmarshal(geometry.Coordinates, &g.Polygon)
+	case GeometryTypeMultiPolygon:
+		return json.Unmarshal(geometry.Coordinates, &g.MultiPolygon)
+	}
+
+	g.Coordinates = geometry.Coordinates
+
+	return nil
+}
+
+// MarshalJSON encodes the geometry as GeoJSON.
+func (g Geometry) MarshalJSON() ([]byte, error) {
+	var coordinates interface{} = g.Coordinates
+
+	switch g.Type {
+	case GeometryTypePolygon:
+		coordinates = g.Polygon
+	case GeometryTypeMultiPolygon:
+		coordinates = g.MultiPolygon
+	}
+
+	return json.Marshal(struct {
+		Type        GeometryType `json:"type"`
+		Coordinates interface{}  `json:"coordinates"`
+	}{g.Type, coordinates})
+}
+
+// Polygons returns the polygons of a Polygon or MultiPolygon geometry.
+func (g Geometry) Polygons() []Polygon {
+	switch g.Type {
+	case GeometryTypePolygon:
+		return []Polygon{g.Polygon}
+	case GeometryTypeMultiPolygon:
+		return g.MultiPolygon
+	}
+
+	return nil
+}
+
+// Feature is a GeoJSON feature: a geometry along with its properties.
+type Feature struct {
+	// The identifier of the feature, a string or a number.
+	ID interface{} `json:"id,omitempty"`
+
+	// The geometry of the feature, which may be nil.
+	Geometry *Geometry `json:"geometry"`
+
+	// The properties of the feature.
+	Properties map[string]interface{} `json:"properties"`
+}
+
+// UnmarshalJSON decodes a GeoJSON feature.
+func (f *Feature) UnmarshalJSON(data []byte) error {
+	type plain Feature
+	feature := struct {
+		Type string `json:"type"`
+		plain
+	}{}
+
+	err := json.Unmarshal(data, &feature)
+	if err != nil {
+		return err
+	}
+
+	if len(feature.Type) > 0 && feature.Type != geoJSONFeature {
+		return fmt.Errorf("geojson: expected a %s, have: %q", geoJSONFeature, feature.Type)
+	}
+
+	*f = Feature(feature.plain)
+
+	return nil
+}
+
+// MarshalJSON encodes the feature as GeoJSON.
+func (f Feature) MarshalJSON() ([]byte, error) {
+	type plain Feature
+	return json.Marshal(struct {
+		Type string `json:"type"`
+		plain
+	}{geoJSONFeature, plain(f)})
+}
+
+// Polygons returns the polygons of the feature geometry.
+func (f Feature) Polygons() []Polygon {
+	if f.Geometry == nil {
+		return nil
+	}
+
+	return f.Geometry.Polygons()
+}
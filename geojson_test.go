@@ -0,0 +1,67 @@
+package weatherkit
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestWeatherAlertAreaSingleFeatureOrGeometry(t *testing.T) {
+	tests := map[string]string{
+		"feature":  `{"type": "Feature", "geometry": {"type": "Polygon", "coordinates": [[[0, 0], [1, 0], [1, 1], [0, 0]]]}, "properties": {"name": "a"}}`,
+		"geometry": `{"type": "Polygon", "coordinates": [[[0, 0], [1, 0], [1, 1], [0, 0]]]}`,
+	}
+
+	for name, data := range tests {
+		area := WeatherAlertArea{}
+
+		err := json.Unmarshal([]byte(data), &area)
+		if err != nil {
+			t.Fatalf("%s: %s", name, err.Error())
+		}
+
+		polygons := area.Polygons()
+		if len(area.Features) != 1 || len(polygons) != 1 || len(polygons[0][0]) != 4 {
+			t.Errorf("%s: expected a single polygon feature, have: %+v", name, area)
+		}
+	}
+}
+
+func TestGeometryKeepsOtherTypes(t *testing.T) {
+	data := []byte(`{"type": "Point", "coordinates": [-74.006, 40.713]}`)
+
+	geometry := Geometry{}
+
+	err := json.Unmarshal(data, &geometry)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(geometry.Polygons()) != 0 {
+		t.Errorf("expected no polygons for a point, have: %v", geometry.Polygons())
+	}
+
+	actual, err := json.Marshal(geometry)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	assertJsonEqual(t, data, actual)
+}
+
+func TestFeatureRejectsOtherObjects(t *testing.T) {
+	feature := Feature{}
+
+	err := json.Unmarshal([]byte(`{"type": "FeatureCollection", "features": []}`), &feature)
+	if err == nil {
+		t.Errorf("expected an error decoding a feature collection as a feature")
+	}
+}
+
+func TestEmptyWeatherAlertAreaIsGeoJSON(t *testing.T) {
+	actual, err := json.Marshal(WeatherAlertArea{})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	assertJsonEqual(t, []byte(`{"type": "FeatureCollection", "features": []}`), actual)
+}
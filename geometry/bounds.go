@@ -0,0 +1,160 @@
+package geometry
+
+import (
+	"math"
+	"sort"
+
+	"github.com/shawntoffel/go-weatherkit"
+)
+
+// BoundingBox is the smallest longitude and latitude range enclosing a shape.
+// Boxes crossing the antimeridian have a West longitude greater than their East longitude,
+// as specified by GeoJSON.
+type BoundingBox struct {
+	West  float64
+	South float64
+	East  float64
+	North float64
+}
+
+// CrossesAntimeridian reports whether the box spans the antimeridian.
+func (b BoundingBox) CrossesAntimeridian() bool {
+	return b.West > b.East
+}
+
+// Contains reports whether the position lies within the box, including its edges.
+func (b BoundingBox) Contains(position weatherkit.Position) bool {
+	if !validPosition(position) {
+		return false
+	}
+
+	latitude := position.Latitude()
+	if latitude < b.South || latitude > b.North {
+		return false
+	}
+
+	longitude := normalizeLongitude(position.Longitude())
+	if longitude == -180 && b.East == 180 {
+		longitude = 180
+	}
+
+	if b.CrossesAntimeridian() {
+		return longitude >= b.West || longitude <= b.East
+	}
+
+	return longitude >= b.West && longitude <= b.East
+}
+
+// Bounds returns the bounding box of every polygon of the area,
+// or false if the area has no polygons.
+func Bounds(area weatherkit.WeatherAlertArea) (BoundingBox, bool) {
+	polygons := area.Polygons()
+
+	boxes := make([]BoundingBox, 0, len(polygons))
+	for _, polygon := range polygons {
+		if len(polygon) > 0 && len(validPositions(polygon[0])) > 0 {
+			boxes = append(boxes, PolygonBounds(polygon))
+		}
+	}
+
+	if len(boxes) < 1 {
+		return BoundingBox{}, false
+	}
+
+	return unionBounds(boxes), true
+}
+
+// PolygonBounds returns the bounding box of the exterior ring of the polygon.
+func PolygonBounds(polygon weatherkit.Polygon) BoundingBox {
+	rings := unwrapPolygon(polygon)
+	if len(rings) < 1 {
+		return BoundingBox{}
+	}
+
+	box := BoundingBox{
+		West:  math.Inf(1),
+		South: math.Inf(1),
+		East:  math.Inf(-1),
+		North: math.Inf(-1),
+	}
+
+	for _, position := range rings[0] {
+		box.West = math.Min(box.West, position.Longitude())
+		box.East = math.Max(box.East, position.Longitude())
+		box.South = math.Min(box.South, position.Latitude())
+		box.North = math.Max(box.North, position.Latitude())
+	}
+
+	if box.East-box.West >= 360 {
+		box.West, box.East = -180, 180
+		return box
+	}
+
+	box.West = normalizeLongitude(box.West)
+	box.East = normalizeLongitude(box.East)
+	if box.East == -180 {
+		box.East = 180
+	}
+
+	return box
+}
+
+// unionBounds returns the smallest box enclosing all boxes. The longitude range is found by
+// leaving out the largest gap between the ranges of the boxes around the globe.
+func unionBounds(boxes []BoundingBox) BoundingBox {
+	type interval struct{ west, east float64 }
+
+	intervals := make([]interval, 0, len(boxes))
+	union := BoundingBox{South: math.Inf(1), North: math.Inf(-1)}
+
+	for _, box := range boxes {
+		union.South = math.Min(union.South, box.South)
+		union.North = math.Max(union.North, box.North)
+
+		east := box.East
+		if box.CrossesAntimeridian() {
+			east += 360
+		}
+		intervals = append(intervals, interval{box.West, east})
+	}
+
+	sort.Slice(intervals, func(i, j int) bool {
+		return intervals[i].west < intervals[j].west
+	})
+
+	// Merge the overlapping ranges, then find the largest uncovered gap.
+	merged := []interval{intervals[0]}
+	for _, next := range intervals[1:] {
+		last := &merged[len(merged)-1]
+		if next.west <= last.east {
+			last.east = math.Max(last.east, next.east)
+			continue
+		}
+		merged = append(merged, next)
+	}
+
+	first, last := merged[0], merged[len(merged)-1]
+
+	gap := first.west + 360 - last.east
+	union.West, union.East = first.west, last.east
+
+	for i := 1; i < len(merged); i++ {
+		if g := merged[i].west - merged[i-1].east; g > gap {
+			gap = g
+			union.West, union.East = merged[i].west, merged[i-1].east+360
+		}
+	}
+
+	if gap <= 0 {
+		union.West, union.East = -180, 180
+		return union
+	}
+
+	union.West = normalizeLongitude(union.West)
+	union.East = normalizeLongitude(union.East)
+	if union.East == -180 {
+		union.East = 180
+	}
+
+	return union
+}
@@ -0,0 +1,88 @@
+package geometry
+
+import (
+	"testing"
+
+	"github.com/shawntoffel/go-weatherkit"
+)
+
+func TestPolygonBounds(t *testing.T) {
+	tests := []struct {
+		polygon weatherkit.Polygon
+		want    BoundingBox
+	}{
+		{weatherkit.Polygon{rectangle(0, 0, 10, 10), rectangle(4, 4, 6, 6)}, BoundingBox{0, 0, 10, 10}},
+		{weatherkit.Polygon{{{170, -10}, {-170, -10}, {-170, 10}, {170, 10}, {170, -10}}}, BoundingBox{170, -10, -170, 10}},
+		{weatherkit.Polygon{{{-170, -10}, {170, -10}, {170, 10}, {-170, 10}, {-170, -10}}}, BoundingBox{170, -10, -170, 10}},
+		{weatherkit.Polygon{rectangle(170, 0, 180, 5)}, BoundingBox{170, 0, 180, 5}},
+	}
+
+	for _, test := range tests {
+		if have := PolygonBounds(test.polygon); have != test.want {
+			t.Errorf("want: %+v, have: %+v", test.want, have)
+		}
+	}
+}
+
+func TestBoundsUnion(t *testing.T) {
+	tests := []struct {
+		area weatherkit.WeatherAlertArea
+		want BoundingBox
+	}{
+		{
+			area(weatherkit.Polygon{rectangle(0, 0, 10, 10)}, weatherkit.Polygon{rectangle(20, -5, 30, 5)}),
+			BoundingBox{0, -5, 30, 10},
+		},
+		{
+			area(weatherkit.Polygon{rectangle(170, -10, 180, 10)}, weatherkit.Polygon{rectangle(-180, -10, -170, 10)}),
+			BoundingBox{170, -10, -170, 10},
+		},
+		{
+			area(weatherkit.Polygon{rectangle(-170, 0, -160, 1)}, weatherkit.Polygon{rectangle(160, 0, 170, 1)}),
+			BoundingBox{160, 0, -160, 1},
+		},
+		{
+			area(weatherkit.Polygon{rectangle(-180, 0, 0, 1)}, weatherkit.Polygon{rectangle(0, -1, 180, 0)}),
+			BoundingBox{-180, -1, 180, 1},
+		},
+	}
+
+	for _, test := range tests {
+		have, ok := Bounds(test.area)
+		if !ok || have != test.want {
+			t.Errorf("want: %+v, have: %+v", test.want, have)
+		}
+	}
+
+	if _, ok := Bounds(weatherkit.WeatherAlertArea{}); ok {
+		t.Errorf("expected no bounds for an empty area")
+	}
+}
+
+func TestBoundingBoxContains(t *testing.T) {
+	crossing := BoundingBox{West: 170, South: -10, East: -170, North: 10}
+
+	if !crossing.CrossesAntimeridian() {
+		t.Errorf("expected the box to cross the antimeridian")
+	}
+
+	tests := map[weatherkit.Position]bool{
+		{175, 0}:  true,
+		{180, 0}:  true,
+		{-180, 0}: true,
+		{-175, 0}: true,
+		{0, 0}:    false,
+		{175, 11}: false,
+	}
+
+	for position, want := range tests {
+		if have := crossing.Contains(position); have != want {
+			t.Errorf("%v want: %t, have: %t", position, want, have)
+		}
+	}
+
+	east := BoundingBox{West: 170, South: 0, East: 180, North: 5}
+	if !east.Contains(weatherkit.Position{-180, 1}) {
+		t.Errorf("expected -180 to match an east edge at 180")
+	}
+}
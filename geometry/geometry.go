@@ -0,0 +1,186 @@
+// Package geometry answers spatial questions about weather alert areas, such as whether
+// a location is affected by an alert.
+//
+// Positions and polygons are the GeoJSON types decoded from WeatherAlertArea. Edges are
+// straight lines in longitude and latitude, as specified by GeoJSON, for containment and
+// bounding boxes; distances and areas are measured on a spherical earth. Shapes crossing
+// the antimeridian may either be split into a multi polygon or use longitudes jumping
+// across it, such as from 179 to -179. Rings encircling a pole are not supported.
+//
+// Positions with a coordinate which is not finite, a latitude outside [-90, 90] or a longitude
+// outside [-360, 360] are left out of rings and contained in nothing.
+package geometry
+
+import (
+	"math"
+
+	"github.com/shawntoffel/go-weatherkit"
+)
+
+// Contains reports whether the position lies inside any polygon of the area.
+func Contains(area weatherkit.WeatherAlertArea, position weatherkit.Position) bool {
+	for _, polygon := range area.Polygons() {
+		if PolygonContains(polygon, position) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ContainsEach reports for every position whether it lies inside the area.
+func ContainsEach(area weatherkit.WeatherAlertArea, positions []weatherkit.Position) []bool {
+	polygons := area.Polygons()
+
+	bounds := make([]BoundingBox, len(polygons))
+	for i, polygon := range polygons {
+		bounds[i] = PolygonBounds(polygon)
+	}
+
+	contained := make([]bool, len(positions))
+
+	for i, position := range positions {
+		for j, polygon := range polygons {
+			if bounds[j].Contains(position) && PolygonContains(polygon, position) {
+				contained[i] = true
+				break
+			}
+		}
+	}
+
+	return contained
+}
+
+// PolygonContains reports whether the position lies inside the exterior ring of the polygon
+// and outside all of its holes. Positions on an edge may be reported either way.
+func PolygonContains(polygon weatherkit.Polygon, position weatherkit.Position) bool {
+	if !validPosition(position) {
+		return false
+	}
+
+	rings := unwrapPolygon(polygon)
+	if len(rings) < 1 {
+		return false
+	}
+
+	// The rings may extend past the antimeridian, so the position is also tried a turn away.
+	for _, shift := range []float64{0, 360, -360} {
+		x, y := position.Longitude()+shift, position.Latitude()
+
+		if !ringContains(rings[0], x, y) {
+			continue
+		}
+
+		inHole := false
+		for _, hole := range rings[1:] {
+			if ringContains(hole, x, y) {
+				inHole = true
+				break
+			}
+		}
+
+		if !inHole {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ringContains casts a ray from the point and counts the edges of the ring it crosses.
+func ringContains(ring []weatherkit.Position, x float64, y float64) bool {
+	inside := false
+
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		xi, yi := ring[i].Longitude(), ring[i].Latitude()
+		xj, yj := ring[j].Longitude(), ring[j].Latitude()
+
+		if (yi > y) != (yj > y) && x < (xj-xi)*(y-yi)/(yj-yi)+xi {
+			inside = !inside
+		}
+	}
+
+	return inside
+}
+
+// unwrapPolygon returns the rings of the polygon with longitudes made continuous, so no edge
+// is longer than half a turn. Holes are placed in the same turn as the exterior ring.
+// Invalid positions are left out, and a polygon without a valid exterior ring has no rings.
+func unwrapPolygon(polygon weatherkit.Polygon) [][]weatherkit.Position {
+	rings := make([][]weatherkit.Position, 0, len(polygon))
+
+	for i, ring := range polygon {
+		ring = validPositions(ring)
+		if len(ring) < 1 {
+			if i == 0 {
+				return nil
+			}
+			continue
+		}
+
+		start := ring[0].Longitude()
+		if len(rings) > 0 {
+			reference := rings[0][0].Longitude()
+			start = reference + normalizeLongitude(start-reference)
+		}
+
+		rings = append(rings, unwrapRing(ring, start))
+	}
+
+	return rings
+}
+
+func unwrapRing(ring []weatherkit.Position, start float64) []weatherkit.Position {
+	unwrapped := make([]weatherkit.Position, len(ring))
+	unwrapped[0] = weatherkit.Position{start, ring[0].Latitude()}
+
+	for i := 1; i < len(ring); i++ {
+		delta := normalizeLongitude(ring[i].Longitude() - ring[i-1].Longitude())
+		unwrapped[i] = weatherkit.Position{unwrapped[i-1].Longitude() + delta, ring[i].Latitude()}
+	}
+
+	return unwrapped
+}
+
+// normalizeLongitude maps the longitude into [-180, 180).
+func normalizeLongitude(longitude float64) float64 {
+	longitude = math.Mod(longitude+180, 360)
+	if longitude < 0 {
+		longitude += 360
+	}
+
+	// Adding a turn to a tiny negative remainder may round up to a full turn.
+	if longitude >= 360 {
+		longitude = 0
+	}
+
+	return longitude - 180
+}
+
+// validPosition reports whether the position has a finite latitude within [-90, 90] and a finite
+// longitude within [-360, 360], allowing for rings extending past the antimeridian.
+func validPosition(position weatherkit.Position) bool {
+	longitude, latitude := position.Longitude(), position.Latitude()
+
+	return math.Abs(latitude) <= 90 && math.Abs(longitude) <= 360
+}
+
+// validPositions returns the valid positions of the ring, reusing the ring when all are valid.
+func validPositions(ring []weatherkit.Position) []weatherkit.Position {
+	for i, position := range ring {
+		if validPosition(position) {
+			continue
+		}
+
+		valid := append([]weatherkit.Position{}, ring[:i]...)
+		for _, position := range ring[i+1:] {
+			if validPosition(position) {
+				valid = append(valid, position)
+			}
+		}
+
+		return valid
+	}
+
+	return ring
+}
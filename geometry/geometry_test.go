@@ -0,0 +1,172 @@
+package geometry
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/shawntoffel/go-weatherkit"
+)
+
+// rectangle returns a closed counterclockwise ring from the west, south corner to the east, north corner.
+func rectangle(west float64, south float64, east float64, north float64) []weatherkit.Position {
+	return []weatherkit.Position{{west, south}, {east, south}, {east, north}, {west, north}, {west, south}}
+}
+
+func area(polygons ...weatherkit.Polygon) weatherkit.WeatherAlertArea {
+	return weatherkit.WeatherAlertArea{
+		Features: []weatherkit.Feature{{
+			Geometry: &weatherkit.Geometry{Type: weatherkit.GeometryTypeMultiPolygon, MultiPolygon: polygons},
+		}},
+	}
+}
+
+func TestPolygonContainsWithHole(t *testing.T) {
+	polygon := weatherkit.Polygon{rectangle(0, 0, 10, 10), rectangle(4, 4, 6, 6)}
+
+	tests := map[weatherkit.Position]bool{
+		{2, 2}:  true,
+		{9, 5}:  true,
+		{5, 5}:  false,
+		{11, 5}: false,
+		{5, -1}: false,
+	}
+
+	for position, want := range tests {
+		if have := PolygonContains(polygon, position); have != want {
+			t.Errorf("%v want: %t, have: %t", position, want, have)
+		}
+	}
+}
+
+func TestContainsMultiPolygon(t *testing.T) {
+	multi := area(weatherkit.Polygon{rectangle(0, 0, 1, 1)}, weatherkit.Polygon{rectangle(5, 5, 6, 6)})
+
+	tests := map[weatherkit.Position]bool{
+		{0.5, 0.5}: true,
+		{5.5, 5.5}: true,
+		{3, 3}:     false,
+	}
+
+	for position, want := range tests {
+		if have := Contains(multi, position); have != want {
+			t.Errorf("%v want: %t, have: %t", position, want, have)
+		}
+	}
+}
+
+func TestContainsAcrossAntimeridian(t *testing.T) {
+	crossing := weatherkit.Polygon{
+		{{170, -10}, {-170, -10}, {-170, 10}, {170, 10}, {170, -10}},
+		{{178, -2}, {-178, -2}, {-178, 2}, {178, 2}, {178, -2}},
+	}
+
+	split := area(
+		weatherkit.Polygon{rectangle(170, -10, 180, 10)},
+		weatherkit.Polygon{rectangle(-180, -10, -170, 10)},
+	)
+
+	tests := map[weatherkit.Position]bool{
+		{175, 0}:    true,
+		{-175, 5}:   true,
+		{179.5, 0}:  false,
+		{-179.5, 0}: false,
+		{0, 0}:      false,
+		{165, 0}:    false,
+		{-165, 0}:   false,
+	}
+
+	for position, want := range tests {
+		if have := Contains(area(crossing), position); have != want {
+			t.Errorf("crossing %v want: %t, have: %t", position, want, have)
+		}
+	}
+
+	for _, position := range []weatherkit.Position{{175, 0}, {-175, 5}, {179.5, 0}, {-179.5, 0}} {
+		if !Contains(split, position) {
+			t.Errorf("split %v: expected the position to be contained", position)
+		}
+	}
+}
+
+func TestContainsEach(t *testing.T) {
+	multi := area(weatherkit.Polygon{rectangle(0, 0, 1, 1)}, weatherkit.Polygon{rectangle(5, 5, 6, 6)})
+
+	positions := []weatherkit.Position{{0.5, 0.5}, {3, 3}, {5.5, 5.5}, {-1, 0.5}}
+	want := []bool{true, false, true, false}
+
+	if have := ContainsEach(multi, positions); !reflect.DeepEqual(have, want) {
+		t.Errorf("want: %v, have: %v", want, have)
+	}
+}
+
+func TestContainsEmptyArea(t *testing.T) {
+	if Contains(weatherkit.WeatherAlertArea{}, weatherkit.Position{0, 0}) {
+		t.Errorf("expected an empty area to contain nothing")
+	}
+
+	if PolygonContains(weatherkit.Polygon{}, weatherkit.Position{0, 0}) {
+		t.Errorf("expected an empty polygon to contain nothing")
+	}
+}
+
+func TestContainsInvalidPositions(t *testing.T) {
+	decoded := weatherkit.WeatherAlertArea{}
+
+	err := json.Unmarshal([]byte(`{"type":"Polygon","coordinates":[[[0,0],[10,0],[1e20,5],[10,10],[0,10],[0,0]]]}`), &decoded)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	done := make(chan bool)
+	go func() {
+		done <- Contains(decoded, weatherkit.Position{5, 5})
+	}()
+
+	select {
+	case contained := <-done:
+		if !contained {
+			t.Errorf("expected the valid positions of the ring to contain the position")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected a huge coordinate to be skipped")
+	}
+
+	polygon := weatherkit.Polygon{rectangle(0, 0, 10, 10)}
+
+	for _, position := range []weatherkit.Position{{math.NaN(), 5}, {math.Inf(1), 5}, {1e20, 5}, {5, 91}} {
+		if PolygonContains(polygon, position) {
+			t.Errorf("%v: expected an invalid position to be contained nowhere", position)
+		}
+	}
+
+	if PolygonContains(weatherkit.Polygon{{{math.NaN(), 0}}, rectangle(0, 0, 10, 10)}, weatherkit.Position{5, 5}) {
+		t.Errorf("expected a polygon without a valid exterior ring to contain nothing")
+	}
+}
+
+func TestNormalizeLongitude(t *testing.T) {
+	tests := map[float64]float64{
+		0:    0,
+		180:  -180,
+		-180: -180,
+		190:  -170,
+		-190: 170,
+		540:  -180,
+		-545: 175,
+	}
+
+	for longitude, want := range tests {
+		if have := normalizeLongitude(longitude); have != want {
+			t.Errorf("%g want: %g, have: %g", longitude, want, have)
+		}
+	}
+
+	for _, longitude := range []float64{-1e-15, 1e20, -1e20, math.MaxFloat64} {
+		if have := normalizeLongitude(longitude); have < -180 || have >= 180 {
+			t.Errorf("%g: expected the longitude to be in range, have: %g", longitude, have)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package geometry
+
+import (
+	"math"
+
+	"github.com/shawntoffel/go-weatherkit"
+)
+
+// EarthRadius is the mean radius of the earth, in meters, used for distances and areas.
+const EarthRadius = 6371008.8
+
+// DistanceToEdge returns the distance in meters from the position to the nearest edge of any
+// polygon of the area, including the edges of holes, whether the position is inside or not.
+// It returns positive infinity if the area has no edges.
+func DistanceToEdge(area weatherkit.WeatherAlertArea, position weatherkit.Position) float64 {
+	distance := math.Inf(1)
+
+	for _, polygon := range area.Polygons() {
+		distance = math.Min(distance, PolygonDistanceToEdge(polygon, position))
+	}
+
+	return distance
+}
+
+// PolygonDistanceToEdge returns the distance in meters from the position to the nearest
+// edge of the polygon, including the edges of its holes.
+func PolygonDistanceToEdge(polygon weatherkit.Polygon, position weatherkit.Position) float64 {
+	point := toVector(position)
+	angle := math.Inf(1)
+
+	for _, ring := range polygon {
+		ring = validPositions(ring)
+
+		for i := 1; i < len(ring); i++ {
+			angle = math.Min(angle, segmentAngle(point, toVector(ring[i-1]), toVector(ring[i])))
+		}
+
+		if len(ring) == 1 {
+			angle = math.Min(angle, angleBetween(point, toVector(ring[0])))
+		}
+	}
+
+	return angle * EarthRadius
+}
+
+// Area returns the area of the polygons of the area, in square meters. Overlapping polygons
+// are counted once for each polygon.
+func Area(area weatherkit.WeatherAlertArea) float64 {
+	total := 0.0
+	for _, polygon := range area.Polygons() {
+		total += PolygonArea(polygon)
+	}
+
+	return total
+}
+
+// PolygonArea returns the area of the polygon excluding its holes, in square meters.
+func PolygonArea(polygon weatherkit.Polygon) float64 {
+	rings := unwrapPolygon(polygon)
+	if len(rings) < 1 {
+		return 0
+	}
+
+	area := math.Abs(ringArea(rings[0]))
+	for _, hole := range rings[1:] {
+		area -= math.Abs(ringArea(hole))
+	}
+
+	return math.Max(area, 0)
+}
+
+// ringArea returns the signed area of the ring on a sphere, using the longitude differences
+// of the continuous ring so rings crossing the antimeridian are measured correctly.
+func ringArea(ring []weatherkit.Position) float64 {
+	n := len(ring)
+	if n < 3 {
+		return 0
+	}
+
+	sum := 0.0
+	for i := 0; i < n; i++ {
+		previous := ring[(i+n-1)%n]
+		next := ring[(i+1)%n]
+
+		sum += (radians(next.Longitude()) - radians(previous.Longitude())) * math.Sin(radians(ring[i].Latitude()))
+	}
+
+	return sum * EarthRadius * EarthRadius / 2
+}
+
+type vector [3]float64
+
+func toVector(position weatherkit.Position) vector {
+	lon, lat := radians(position.Longitude()), radians(position.Latitude())
+	return vector{math.Cos(lat) * math.Cos(lon), math.Cos(lat) * math.Sin(lon), math.Sin(lat)}
+}
+
+func (a vector) dot(b vector) float64 {
+	return a[0]*b[0] + a[1]*b[1] + a[2]*b[2]
+}
+
+func (a vector) cross(b vector) vector {
+	return vector{a[1]*b[2] - a[2]*b[1], a[2]*b[0] - a[0]*b[2], a[0]*b[1] - a[1]*b[0]}
+}
+
+func (a vector) scale(s float64) vector {
+	return vector{a[0] * s, a[1] * s, a[2] * s}
+}
+
+func (a vector) sub(b vector) vector {
+	return vector{a[0] - b[0], a[1] - b[1], a[2] - b[2]}
+}
+
+func (a vector) norm() float64 {
+	return math.Sqrt(a.dot(a))
+}
+
+// angleBetween returns the angle between the unit vectors, in radians.
+func angleBetween(a vector, b vector) float64 {
+	return math.Atan2(a.cross(b).norm(), a.dot(b))
+}
+
+// segmentAngle returns the angle from the point to the nearest point of the great circle arc from a to b.
+func segmentAngle(point vector, a vector, b vector) float64 {
+	normal := a.cross(b)
+	length := normal.norm()
+
+	if length < 1e-15 {
+		return angleBetween(point, a)
+	}
+	normal = normal.scale(1 / length)
+
+	// The projection of the point onto the great circle lies on the arc if it is between a and b.
+	projected := point.sub(normal.scale(point.dot(normal)))
+	if projected.norm() > 1e-15 && a.cross(projected).dot(normal) >= 0 && projected.cross(b).dot(normal) >= 0 {
+		return math.Abs(math.Asin(math.Max(-1, math.Min(1, point.dot(normal)))))
+	}
+
+	return math.Min(angleBetween(point, a), angleBetween(point, b))
+}
+
+func radians(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
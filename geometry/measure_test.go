@@ -0,0 +1,65 @@
+package geometry
+
+import (
+	"math"
+	"testing"
+
+	"github.com/shawntoffel/go-weatherkit"
+)
+
+// degree is the length of one degree of a great circle, in meters.
+var degree = EarthRadius * math.Pi / 180
+
+func assertWithin(t *testing.T, name string, have float64, want float64, tolerance float64) {
+	t.Helper()
+
+	if math.Abs(have-want) > tolerance {
+		t.Errorf("%s want: %f ± %f, have: %f", name, want, tolerance, have)
+	}
+}
+
+func TestPolygonArea(t *testing.T) {
+	// The area between the equator and the first parallel, one degree of longitude wide.
+	want := EarthRadius * EarthRadius * radians(1) * math.Sin(radians(1))
+
+	assertWithin(t, "square", PolygonArea(weatherkit.Polygon{rectangle(0, 0, 1, 1)}), want, want*1e-6)
+
+	reversed := weatherkit.Polygon{{{0, 0}, {0, 1}, {1, 1}, {1, 0}, {0, 0}}}
+	assertWithin(t, "clockwise", PolygonArea(reversed), want, want*1e-6)
+
+	crossing := weatherkit.Polygon{{{179.5, 0}, {-179.5, 0}, {-179.5, 1}, {179.5, 1}, {179.5, 0}}}
+	assertWithin(t, "antimeridian", PolygonArea(crossing), want, want*1e-6)
+
+	holed := weatherkit.Polygon{rectangle(0, 0, 2, 1), rectangle(0.5, 0, 1.5, 1)}
+	assertWithin(t, "hole", PolygonArea(holed), want, want*1e-6)
+
+	multi := area(weatherkit.Polygon{rectangle(0, 0, 1, 1)}, weatherkit.Polygon{rectangle(10, 0, 11, 1)})
+	assertWithin(t, "multi polygon", Area(multi), 2*want, want*1e-6)
+}
+
+func TestPolygonDistanceToEdge(t *testing.T) {
+	square := weatherkit.Polygon{rectangle(0, 0, 2, 2)}
+
+	// The nearest edges of the center are the meridians, slightly closer than the equator.
+	inside := math.Asin(math.Cos(radians(1))*math.Sin(radians(1))) * EarthRadius
+	assertWithin(t, "inside", PolygonDistanceToEdge(square, weatherkit.Position{1, 1}), inside, 1)
+
+	assertWithin(t, "below", PolygonDistanceToEdge(square, weatherkit.Position{1, -1}), degree, 1)
+
+	corner := PolygonDistanceToEdge(square, weatherkit.Position{-1, -1})
+	assertWithin(t, "corner", corner, math.Acos(math.Cos(radians(1))*math.Cos(radians(1)))*EarthRadius, 1)
+
+	holed := weatherkit.Polygon{rectangle(0, 0, 10, 10), rectangle(4, 0.5, 6, 9.5)}
+	assertWithin(t, "hole", PolygonDistanceToEdge(holed, weatherkit.Position{5, 0.25}), 0.25*degree, 10)
+}
+
+func TestDistanceToEdgeAcrossAntimeridian(t *testing.T) {
+	crossing := area(weatherkit.Polygon{{{170, -10}, {-170, -10}, {-170, 10}, {170, 10}, {170, -10}}})
+
+	assertWithin(t, "east", DistanceToEdge(crossing, weatherkit.Position{-165, 0}), 5*degree, 1)
+	assertWithin(t, "inside", DistanceToEdge(crossing, weatherkit.Position{180, 0}), 10*degree, 1)
+
+	if have := DistanceToEdge(weatherkit.WeatherAlertArea{}, weatherkit.Position{0, 0}); !math.IsInf(have, 1) {
+		t.Errorf("expected an infinite distance for an empty area, have: %f", have)
+	}
+}
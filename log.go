@@ -0,0 +1,117 @@
+package weatherkit
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Logger receives structured log events as a message followed by alternating keys and values.
+// A *slog.Logger satisfies Logger.
+type Logger interface {
+	DebugContext(ctx context.Context, msg string, args ...interface{})
+	InfoContext(ctx context.Context, msg string, args ...interface{})
+	WarnContext(ctx context.Context, msg string, args ...interface{})
+	ErrorContext(ctx context.Context, msg string, args ...interface{})
+}
+
+const redacted = "REDACTED"
+
+// redactHeaders returns a copy of the headers which is safe to log.
+func redactHeaders(header http.Header) http.Header {
+	safe := header.Clone()
+
+	if len(safe.Values("Authorization")) > 0 {
+		safe.Set("Authorization", redacted)
+	}
+
+	return safe
+}
+
+func (d *Client) logDebug(ctx context.Context, msg string, args ...interface{}) {
+	if d.Logger != nil {
+		d.Logger.DebugContext(ctx, msg, args...)
+	}
+}
+
+func (d *Client) logResult(ctx context.Context, call *Call, duration time.Duration, err error) {
+	if d.Logger == nil {
+		return
+	}
+
+	args := []interface{}{
+		"endpoint", call.Endpoint,
+		"url", call.HTTPRequest.URL.String(),
+		"duration", duration,
+	}
+
+	if call.HTTPResponse != nil {
+		args = append(args, "status", call.HTTPResponse.StatusCode)
+
+		if body, ok := call.HTTPResponse.Body.(*countingReadCloser); ok {
+			args = append(args, "bytes", body.n)
+		}
+	}
+
+	if err != nil {
+		d.Logger.WarnContext(ctx, "request failed", append(args, "error", err)...)
+		return
+	}
+
+	d.Logger.DebugContext(ctx, "request completed", args...)
+}
+
+func (d *Client) logRetry(ctx context.Context, endpoint Endpoint, attempt int, delay time.Duration, response *http.Response, err error) {
+	if d.Logger == nil {
+		return
+	}
+
+	args := []interface{}{
+		"endpoint", endpoint,
+		"attempt", attempt,
+		"delay", delay,
+	}
+
+	if response != nil {
+		args = append(args, "status", response.StatusCode)
+	}
+
+	if err != nil {
+		args = append(args, "error", err)
+	}
+
+	d.Logger.WarnContext(ctx, "retrying request", args...)
+}
+
+func (c *CredentialedClient) logger() Logger {
+	if c.options.logger != nil {
+		return c.options.logger
+	}
+
+	return c.options.client.Logger
+}
+
+func (c *CredentialedClient) logDebug(ctx context.Context, msg string, args ...interface{}) {
+	if logger := c.logger(); logger != nil {
+		logger.DebugContext(ctx, msg, args...)
+	}
+}
+
+func (c *CredentialedClient) logError(ctx context.Context, msg string, args ...interface{}) {
+	if logger := c.logger(); logger != nil {
+		logger.ErrorContext(ctx, msg, args...)
+	}
+}
+
+// countingReadCloser counts the bytes read from a response body.
+type countingReadCloser struct {
+	io.ReadCloser
+	n int64
+}
+
+func (c *countingReadCloser) Read(p []byte) (int, error) {
+	n, err := c.ReadCloser.Read(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,104 @@
+package weatherkit
+
+import (
+	"strconv"
+	"time"
+)
+
+// Metrics receives measurements taken by the clients.
+// Implementations must be safe for concurrent use.
+type Metrics interface {
+	// IncCounter increments the named counter by one.
+	IncCounter(name string, labels Labels)
+
+	// ObserveHistogram records a value in the named histogram.
+	ObserveHistogram(name string, value float64, labels Labels)
+}
+
+// Labels are the dimensions of a measurement.
+type Labels map[string]string
+
+// Names of the measurements reported to Metrics.
+const (
+	// Counts requests by endpoint and status, with a status of "error" for transport errors.
+	MetricRequests = "weatherkit_requests_total"
+
+	// Observes the duration of requests in seconds by endpoint, including retries.
+	MetricRequestDuration = "weatherkit_request_duration_seconds"
+
+	// Counts response bodies which could not be decoded by endpoint.
+	MetricDecodeFailures = "weatherkit_decode_failures_total"
+
+	// Counts retried requests by endpoint.
+	MetricRetries = "weatherkit_retries_total"
+
+	// Counts responses served from the Cache by endpoint. Weather data sets are counted individually.
+	MetricCacheHits = "weatherkit_cache_hits_total"
+
+	// Counts responses missing from the Cache by endpoint. Weather data sets are counted individually.
+	MetricCacheMisses = "weatherkit_cache_misses_total"
+
+	// Counts signed developer tokens by result, either "success" or "failure".
+	MetricTokenSignings = "weatherkit_token_signings_total"
+)
+
+var metricDescriptions = map[string]string{
+	MetricRequests:        "WeatherKit API requests by endpoint and status.",
+	MetricRequestDuration: "WeatherKit API request duration in seconds by endpoint.",
+	MetricDecodeFailures:  "WeatherKit API responses which could not be decoded by endpoint.",
+	MetricRetries:         "WeatherKit API request retries by endpoint.",
+	MetricCacheHits:       "WeatherKit responses served from the cache by endpoint.",
+	MetricCacheMisses:     "WeatherKit responses missing from the cache by endpoint.",
+	MetricTokenSignings:   "WeatherKit developer tokens signed by result.",
+}
+
+func (d *Client) incCounter(name string, labels Labels) {
+	if d.Metrics != nil {
+		d.Metrics.IncCounter(name, labels)
+	}
+}
+
+func (d *Client) observeRequest(call *Call, duration time.Duration) {
+	if d.Metrics == nil {
+		return
+	}
+
+	status := "error"
+	if call.HTTPResponse != nil {
+		status = strconv.Itoa(call.HTTPResponse.StatusCode)
+	}
+
+	d.Metrics.IncCounter(MetricRequests, Labels{"endpoint": string(call.Endpoint), "status": status})
+	d.Metrics.ObserveHistogram(MetricRequestDuration, duration.Seconds(), Labels{"endpoint": string(call.Endpoint)})
+}
+
+func (d *Client) observeCache(endpoint Endpoint, hit bool) {
+	if hit {
+		d.incCounter(MetricCacheHits, Labels{"endpoint": string(endpoint)})
+		return
+	}
+
+	d.incCounter(MetricCacheMisses, Labels{"endpoint": string(endpoint)})
+}
+
+func (c *CredentialedClient) metrics() Metrics {
+	if c.options.metrics != nil {
+		return c.options.metrics
+	}
+
+	return c.options.client.Metrics
+}
+
+func (c *CredentialedClient) observeSigning(err error) {
+	metrics := c.metrics()
+	if metrics == nil {
+		return
+	}
+
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+
+	metrics.IncCounter(MetricTokenSignings, Labels{"result": result})
+}
@@ -0,0 +1,170 @@
+package weatherkit
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type testMetrics struct {
+	mu           sync.Mutex
+	counters     map[string]int
+	observations map[string][]float64
+}
+
+func newTestMetrics() *testMetrics {
+	return &testMetrics{
+		counters:     map[string]int{},
+		observations: map[string][]float64{},
+	}
+}
+
+func (m *testMetrics) IncCounter(name string, labels Labels) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.counters[name+formatPromLabels(labels, "")]++
+}
+
+func (m *testMetrics) ObserveHistogram(name string, value float64, labels Labels) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := name + formatPromLabels(labels, "")
+	m.observations[key] = append(m.observations[key], value)
+}
+
+func (m *testMetrics) counter(name string, labels Labels) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.counters[name+formatPromLabels(labels, "")]
+}
+
+func TestMetricsRequestsAndRetries(t *testing.T) {
+	var calls int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintln(w, `{"serviceName":"Weather"}`)
+	}))
+	defer server.Close()
+
+	metrics := newTestMetrics()
+
+	client := Client{
+		BaseURL:     server.URL,
+		Metrics:     metrics,
+		RetryPolicy: &ExponentialBackoff{BaseDelay: time.Millisecond},
+	}
+
+	_, err := client.Attribution(context.TODO(), AttributionRequest{Language: "en"})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	endpoint := Labels{"endpoint": "attribution"}
+
+	if have := metrics.counter(MetricRequests, Labels{"endpoint": "attribution", "status": "200"}); have != 1 {
+		t.Errorf("want: %d requests, have: %d", 1, have)
+	}
+
+	if have := metrics.counter(MetricRetries, endpoint); have != 1 {
+		t.Errorf("want: %d retries, have: %d", 1, have)
+	}
+
+	if have := len(metrics.observations[MetricRequestDuration+formatPromLabels(endpoint, "")]); have != 1 {
+		t.Errorf("want: %d duration observations, have: %d", 1, have)
+	}
+}
+
+func TestMetricsDecodeFailures(t *testing.T) {
+	server := getMockServer([]byte("{not json"), http.StatusOK)
+	defer server.Close()
+
+	metrics := newTestMetrics()
+	client := Client{BaseURL: server.URL, Metrics: metrics}
+
+	_, err := client.Weather(context.TODO(), "", WeatherRequest{})
+	if err == nil {
+		t.Fatal("expected request to error")
+	}
+
+	if have := metrics.counter(MetricDecodeFailures, Labels{"endpoint": "weather"}); have != 1 {
+		t.Errorf("want: %d decode failures, have: %d", 1, have)
+	}
+}
+
+func TestMetricsCacheHits(t *testing.T) {
+	server, _ := getMockDataSetServer(t, time.Now().Add(time.Hour))
+	defer server.Close()
+
+	metrics := newTestMetrics()
+	client := Client{BaseURL: server.URL, Metrics: metrics, Cache: NewMemoryCache(0)}
+
+	request := WeatherRequest{DataSets: DataSets{DataSetForecastDaily, DataSetForecastHourly}}
+
+	for i := 0; i < 2; i++ {
+		_, err := client.Weather(context.TODO(), "", request)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+
+	endpoint := Labels{"endpoint": "weather"}
+
+	if have := metrics.counter(MetricCacheMisses, endpoint); have != 2 {
+		t.Errorf("want: %d cache misses, have: %d", 2, have)
+	}
+
+	if have := metrics.counter(MetricCacheHits, endpoint); have != 2 {
+		t.Errorf("want: %d cache hits, have: %d", 2, have)
+	}
+}
+
+func TestMetricsTokenSignings(t *testing.T) {
+	pk, err := createPrivateKeyPEM()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server, _, err := getMockServerWithFileData("testdata/current_weather.json", http.StatusOK)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer server.Close()
+
+	metrics := newTestMetrics()
+
+	client := NewCredentialedClient(Credentials{
+		KeyID:      "key",
+		TeamID:     "team",
+		ServiceID:  "service",
+		PrivateKey: pk,
+	}, WithBaseURL(server.URL), WithMetrics(metrics), WithoutCache())
+
+	for i := 0; i < 2; i++ {
+		_, err = client.Weather(context.TODO(), WeatherRequest{})
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+
+	if have := metrics.counter(MetricTokenSignings, Labels{"result": "success"}); have != 2 {
+		t.Errorf("want: %d signings, have: %d", 2, have)
+	}
+
+	if have := metrics.counter(MetricRequests, Labels{"endpoint": "weather", "status": "200"}); have != 2 {
+		t.Errorf("want: %d requests, have: %d", 2, have)
+	}
+}
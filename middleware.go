@@ -0,0 +1,43 @@
+package weatherkit
+
+import (
+	"context"
+	"net/http"
+)
+
+// Call is a single API call passing through the Middleware chain of a Client.
+type Call struct {
+	// The typed request: WeatherRequest, AvailabilityRequest, WeatherAlertRequest or AttributionRequest.
+	Request interface{}
+
+	// The endpoint the request is sent to.
+	Endpoint Endpoint
+
+	// The outgoing HTTP request. Middleware may add headers or replace it before calling the next Handler.
+	HTTPRequest *http.Request
+
+	// The HTTP response of the final attempt, set once the next Handler has sent the request.
+	// Its body has already been consumed when the next Handler returns.
+	HTTPResponse *http.Response
+
+	// A pointer to the value the response is decoded into, e.g. *WeatherResponse.
+	// It is populated once the next Handler returns without error.
+	// Middleware may populate it itself instead of calling the next Handler.
+	Result interface{}
+}
+
+// Handler performs an API call.
+type Handler func(ctx context.Context, call *Call) error
+
+// Middleware wraps a Handler with additional behavior, such as logging, metrics or header injection.
+// Calling next continues the chain; returning without calling it short-circuits the call.
+type Middleware func(next Handler) Handler
+
+// chain wraps the handler with the middleware, the first middleware being the outermost.
+func chain(middleware []Middleware, handler Handler) Handler {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](handler)
+	}
+
+	return handler
+}
@@ -0,0 +1,138 @@
+package weatherkit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestMiddlewareOrderAndVisibility(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Injected", r.Header.Get("X-Injected"))
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintln(w, `{"serviceName":"Weather"}`)
+	}))
+	defer server.Close()
+
+	events := []string{}
+
+	record := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return func(ctx context.Context, call *Call) error {
+				events = append(events, "before "+name)
+				err := next(ctx, call)
+				events = append(events, "after "+name)
+				return err
+			}
+		}
+	}
+
+	inject := func(next Handler) Handler {
+		return func(ctx context.Context, call *Call) error {
+			call.HTTPRequest.Header.Set("X-Injected", "yes")
+			return next(ctx, call)
+		}
+	}
+
+	var seen *Call
+	inspect := func(next Handler) Handler {
+		return func(ctx context.Context, call *Call) error {
+			err := next(ctx, call)
+			seen = call
+			return err
+		}
+	}
+
+	client := Client{
+		BaseURL:    server.URL,
+		Middleware: []Middleware{record("first"), inject, inspect, record("second")},
+	}
+
+	_, err := client.Attribution(context.TODO(), AttributionRequest{Language: "en"})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	want := "before first,before second,after second,after first"
+	if have := strings.Join(events, ","); have != want {
+		t.Errorf("want: %s, have: %s", want, have)
+	}
+
+	request, ok := seen.Request.(AttributionRequest)
+	if !ok || request.Language != "en" {
+		t.Errorf("expected the typed request, have: %#v", seen.Request)
+	}
+
+	if seen.Endpoint != EndpointAttribution {
+		t.Errorf("want: %s, have: %s", EndpointAttribution, seen.Endpoint)
+	}
+
+	if seen.HTTPResponse == nil || seen.HTTPResponse.Header.Get("X-Injected") != "yes" {
+		t.Errorf("expected the injected header to reach the server")
+	}
+
+	result, ok := seen.Result.(*AttributionResponse)
+	if !ok || result.ServiceName != "Weather" {
+		t.Errorf("expected the decoded result, have: %#v", seen.Result)
+	}
+}
+
+func TestMiddlewareShortCircuit(t *testing.T) {
+	var calls int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintln(w, `{}`)
+	}))
+	defer server.Close()
+
+	injected := errors.New("injected fault")
+
+	fault := func(next Handler) Handler {
+		return func(ctx context.Context, call *Call) error {
+			if _, ok := call.Request.(WeatherAlertRequest); ok {
+				return injected
+			}
+			return next(ctx, call)
+		}
+	}
+
+	stub := func(next Handler) Handler {
+		return func(ctx context.Context, call *Call) error {
+			if result, ok := call.Result.(*WeatherResponse); ok {
+				result.CurrentWeather = &CurrentWeather{CurrentWeatherData: CurrentWeatherData{Temperature: 21}}
+				return nil
+			}
+			return next(ctx, call)
+		}
+	}
+
+	client := Client{
+		BaseURL:    server.URL,
+		Middleware: []Middleware{fault, stub},
+	}
+
+	_, err := client.Alert(context.TODO(), "", WeatherAlertRequest{ID: "id", Language: "en"})
+	if !errors.Is(err, injected) {
+		t.Errorf("expected %v, got: %v", injected, err)
+	}
+
+	weather, err := client.Weather(context.TODO(), "", WeatherRequest{})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if weather.CurrentWeather == nil || weather.CurrentWeather.Temperature != 21 {
+		t.Errorf("expected the stubbed result, have: %+v", weather)
+	}
+
+	if calls != 0 {
+		t.Errorf("want: %d calls, have: %d", 0, calls)
+	}
+}
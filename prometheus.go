@@ -0,0 +1,203 @@
+package weatherkit
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultHistogramBuckets are the upper bounds, in seconds, used by PrometheusMetrics when none are given.
+var DefaultHistogramBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// NewPrometheusMetrics creates Metrics which are served in the Prometheus text exposition format.
+// Histograms use the given bucket upper bounds, or DefaultHistogramBuckets if none are given.
+func NewPrometheusMetrics(buckets ...float64) *PrometheusMetrics {
+	if len(buckets) < 1 {
+		buckets = DefaultHistogramBuckets
+	}
+
+	sorted := append([]float64{}, buckets...)
+	sort.Float64s(sorted)
+
+	return &PrometheusMetrics{
+		buckets:    sorted,
+		counters:   map[string]map[string]float64{},
+		histograms: map[string]map[string]*promHistogram{},
+	}
+}
+
+// PrometheusMetrics is Metrics which also serves the collected measurements over HTTP
+// in the Prometheus text exposition format.
+// Construct with NewPrometheusMetrics.
+type PrometheusMetrics struct {
+	mu         sync.Mutex
+	buckets    []float64
+	counters   map[string]map[string]float64
+	histograms map[string]map[string]*promHistogram
+}
+
+type promHistogram struct {
+	labels Labels
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// IncCounter implements Metrics.
+func (m *PrometheusMetrics) IncCounter(name string, labels Labels) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	series, ok := m.counters[name]
+	if !ok {
+		series = map[string]float64{}
+		m.counters[name] = series
+	}
+
+	series[formatPromLabels(labels, "")]++
+}
+
+// ObserveHistogram implements Metrics.
+func (m *PrometheusMetrics) ObserveHistogram(name string, value float64, labels Labels) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	series, ok := m.histograms[name]
+	if !ok {
+		series = map[string]*promHistogram{}
+		m.histograms[name] = series
+	}
+
+	key := formatPromLabels(labels, "")
+
+	histogram, ok := series[key]
+	if !ok {
+		histogram = &promHistogram{
+			labels: copyLabels(labels),
+			counts: make([]uint64, len(m.buckets)),
+		}
+		series[key] = histogram
+	}
+
+	for i, bound := range m.buckets {
+		if value <= bound {
+			histogram.counts[i]++
+		}
+	}
+
+	histogram.count++
+	histogram.sum += value
+}
+
+// ServeHTTP writes the collected measurements in the Prometheus text exposition format.
+func (m *PrometheusMetrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = w.Write(m.render())
+}
+
+func (m *PrometheusMetrics) render() []byte {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	buf := &bytes.Buffer{}
+
+	for _, name := range sortedKeys(m.counters) {
+		writePromHeader(buf, name, "counter")
+
+		series := m.counters[name]
+		for _, key := range sortedKeys(series) {
+			fmt.Fprintf(buf, "%s%s %s\n", name, key, formatPromValue(series[key]))
+		}
+	}
+
+	for _, name := range sortedKeys(m.histograms) {
+		writePromHeader(buf, name, "histogram")
+
+		series := m.histograms[name]
+		for _, key := range sortedKeys(series) {
+			histogram := series[key]
+
+			for i, bound := range m.buckets {
+				le := formatPromLabels(histogram.labels, formatPromValue(bound))
+				fmt.Fprintf(buf, "%s_bucket%s %d\n", name, le, histogram.counts[i])
+			}
+
+			fmt.Fprintf(buf, "%s_bucket%s %d\n", name, formatPromLabels(histogram.labels, "+Inf"), histogram.count)
+			fmt.Fprintf(buf, "%s_sum%s %s\n", name, key, formatPromValue(histogram.sum))
+			fmt.Fprintf(buf, "%s_count%s %d\n", name, key, histogram.count)
+		}
+	}
+
+	return buf.Bytes()
+}
+
+func writePromHeader(buf *bytes.Buffer, name string, kind string) {
+	if help, ok := metricDescriptions[name]; ok {
+		fmt.Fprintf(buf, "# HELP %s %s\n", name, help)
+	}
+
+	fmt.Fprintf(buf, "# TYPE %s %s\n", name, kind)
+}
+
+// formatPromLabels renders the labels sorted by name, followed by an le label if given.
+func formatPromLabels(labels Labels, le string) string {
+	names := sortedKeys(map[string]string(labels))
+	if len(names) < 1 && len(le) < 1 {
+		return ""
+	}
+
+	pairs := make([]string, 0, len(names)+1)
+	for _, name := range names {
+		pairs = append(pairs, name+`="`+escapePromLabel(labels[name])+`"`)
+	}
+
+	if len(le) > 0 {
+		pairs = append(pairs, `le="`+le+`"`)
+	}
+
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+var promLabelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapePromLabel(value string) string {
+	return promLabelEscaper.Replace(value)
+}
+
+func formatPromValue(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	}
+
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+func copyLabels(labels Labels) Labels {
+	copied := make(Labels, len(labels))
+	for name, value := range labels {
+		copied[name] = value
+	}
+
+	return copied
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,51 @@
+package weatherkit
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestPrometheusMetricsExposition(t *testing.T) {
+	metrics := NewPrometheusMetrics(0.5, 0.1)
+
+	metrics.IncCounter(MetricRequests, Labels{"status": "200", "endpoint": "weather"})
+	metrics.IncCounter(MetricRequests, Labels{"status": "200", "endpoint": "weather"})
+	metrics.IncCounter(MetricRequests, Labels{"status": "429", "endpoint": "weather"})
+	metrics.IncCounter("custom_total", Labels{"note": "a \"quoted\"\nvalue"})
+	metrics.ObserveHistogram(MetricRequestDuration, 0.05, Labels{"endpoint": "weather"})
+	metrics.ObserveHistogram(MetricRequestDuration, 0.3, Labels{"endpoint": "weather"})
+	metrics.ObserveHistogram(MetricRequestDuration, 2, Labels{"endpoint": "weather"})
+
+	recorder := httptest.NewRecorder()
+	metrics.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	body, _ := ioutil.ReadAll(recorder.Body)
+
+	want := strings.Join([]string{
+		`# TYPE custom_total counter`,
+		`custom_total{note="a \"quoted\"\nvalue"} 1`,
+		`# HELP weatherkit_requests_total WeatherKit API requests by endpoint and status.`,
+		`# TYPE weatherkit_requests_total counter`,
+		`weatherkit_requests_total{endpoint="weather",status="200"} 2`,
+		`weatherkit_requests_total{endpoint="weather",status="429"} 1`,
+		`# HELP weatherkit_request_duration_seconds WeatherKit API request duration in seconds by endpoint.`,
+		`# TYPE weatherkit_request_duration_seconds histogram`,
+		`weatherkit_request_duration_seconds_bucket{endpoint="weather",le="0.1"} 1`,
+		`weatherkit_request_duration_seconds_bucket{endpoint="weather",le="0.5"} 2`,
+		`weatherkit_request_duration_seconds_bucket{endpoint="weather",le="+Inf"} 3`,
+		`weatherkit_request_duration_seconds_sum{endpoint="weather"} 2.35`,
+		`weatherkit_request_duration_seconds_count{endpoint="weather"} 3`,
+		``,
+	}, "\n")
+
+	if string(body) != want {
+		t.Errorf("\nwant:\n%s\nhave:\n%s", want, body)
+	}
+
+	if contentType := recorder.Header().Get("Content-Type"); !strings.HasPrefix(contentType, "text/plain; version=0.0.4") {
+		t.Errorf("unexpected content type: %s", contentType)
+	}
+}
@@ -0,0 +1,112 @@
+package weatherkit
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrQuotaExhausted is returned when a request is refused because the Quota budget has been used up.
+var ErrQuotaExhausted = errors.New("request quota exhausted")
+
+// NewQuota creates a Quota which allows budget requests in total.
+// A budget of zero or less only counts requests without refusing any.
+func NewQuota(budget int64) *Quota {
+	return &Quota{
+		budget: budget,
+		counts: map[Endpoint]int64{},
+	}
+}
+
+// Quota records the number of requests sent per endpoint and enforces an optional budget.
+// Retried requests are counted once per attempt, matching how the API meters them.
+// Construct with NewQuota.
+type Quota struct {
+	mu     sync.Mutex
+	budget int64
+	total  int64
+	counts map[Endpoint]int64
+}
+
+// Budget returns the total number of requests allowed, or zero if unlimited.
+func (q *Quota) Budget() int64 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.budget <= 0 {
+		return 0
+	}
+
+	return q.budget
+}
+
+// Total returns the number of requests sent to all endpoints.
+func (q *Quota) Total() int64 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return q.total
+}
+
+// Count returns the number of requests sent to the endpoint.
+func (q *Quota) Count(endpoint Endpoint) int64 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return q.counts[endpoint]
+}
+
+// Counts returns a copy of the number of requests sent per endpoint.
+func (q *Quota) Counts() map[Endpoint]int64 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	counts := make(map[Endpoint]int64, len(q.counts))
+	for endpoint, count := range q.counts {
+		counts[endpoint] = count
+	}
+
+	return counts
+}
+
+// Remaining returns the number of requests left in the budget, or -1 if unlimited.
+func (q *Quota) Remaining() int64 {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.budget <= 0 {
+		return -1
+	}
+
+	if q.total >= q.budget {
+		return 0
+	}
+
+	return q.budget - q.total
+}
+
+// Reset clears all counts, e.g. at the start of a new billing period.
+func (q *Quota) Reset() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.total = 0
+	q.counts = map[Endpoint]int64{}
+}
+
+func (q *Quota) reserve(endpoint Endpoint) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.budget > 0 && q.total >= q.budget {
+		return ErrQuotaExhausted
+	}
+
+	if q.counts == nil {
+		q.counts = map[Endpoint]int64{}
+	}
+
+	q.total++
+	q.counts[endpoint]++
+
+	return nil
+}
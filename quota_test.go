@@ -0,0 +1,90 @@
+package weatherkit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestQuotaCountsPerEndpoint(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintln(w, `{}`)
+	}))
+	defer server.Close()
+
+	quota := NewQuota(0)
+	client := Client{BaseURL: server.URL, Quota: quota}
+
+	for i := 0; i < 2; i++ {
+		_, err := client.Weather(context.TODO(), "", WeatherRequest{})
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+
+	_, err := client.Attribution(context.TODO(), AttributionRequest{Language: "en"})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if have := quota.Count(EndpointWeather); have != 2 {
+		t.Errorf("want: %d, have: %d", 2, have)
+	}
+
+	if have := quota.Count(EndpointAttribution); have != 1 {
+		t.Errorf("want: %d, have: %d", 1, have)
+	}
+
+	if have := quota.Total(); have != 3 {
+		t.Errorf("want: %d, have: %d", 3, have)
+	}
+
+	if have := quota.Remaining(); have != -1 {
+		t.Errorf("want: %d, have: %d", -1, have)
+	}
+}
+
+func TestQuotaRefusesOnceExhausted(t *testing.T) {
+	var calls int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintln(w, `["currentWeather"]`)
+	}))
+	defer server.Close()
+
+	quota := NewQuota(2)
+	client := Client{BaseURL: server.URL, Quota: quota}
+
+	for i := 0; i < 2; i++ {
+		_, err := client.Availability(context.TODO(), "", AvailabilityRequest{})
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+
+	_, err := client.Availability(context.TODO(), "", AvailabilityRequest{})
+	if !errors.Is(err, ErrQuotaExhausted) {
+		t.Errorf("expected %v, got: %v", ErrQuotaExhausted, err)
+	}
+
+	if calls != 2 {
+		t.Errorf("want: %d calls, have: %d", 2, calls)
+	}
+
+	if have := quota.Remaining(); have != 0 {
+		t.Errorf("want: %d, have: %d", 0, have)
+	}
+
+	quota.Reset()
+
+	if have := quota.Remaining(); have != 2 {
+		t.Errorf("want: %d, have: %d", 2, have)
+	}
+}
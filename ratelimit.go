@@ -0,0 +1,97 @@
+package weatherkit
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// RateLimiter limits the rate at which requests are sent.
+type RateLimiter interface {
+	// Wait blocks until a request may be sent or the context is done.
+	Wait(ctx context.Context) error
+}
+
+// NewTokenBucket creates a RateLimiter which allows rate requests per second on average
+// with bursts of up to burst requests. A rate of zero or less disables limiting.
+func NewTokenBucket(rate float64, burst int) *TokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &TokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// TokenBucket is a token bucket RateLimiter.
+// Waiting callers are served in the order they called Wait.
+// Construct with NewTokenBucket.
+type TokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// Wait implements RateLimiter.
+// It returns immediately with an error if the wait would exceed the context deadline.
+func (b *TokenBucket) Wait(ctx context.Context) error {
+	if b.rate <= 0 {
+		return ctx.Err()
+	}
+
+	wait := b.reserve(time.Now())
+	if wait <= 0 {
+		return nil
+	}
+
+	if !fitsDeadline(ctx, wait) {
+		b.release()
+		return fmt.Errorf("rate limit wait of %s would exceed the context deadline: %w", wait, context.DeadlineExceeded)
+	}
+
+	err := sleep(ctx, wait)
+	if err != nil {
+		b.release()
+	}
+
+	return err
+}
+
+// reserve takes a token, returning how long to wait until it becomes available.
+func (b *TokenBucket) reserve(now time.Time) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if now.After(b.last) {
+		b.tokens += now.Sub(b.last).Seconds() * b.rate
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+		b.last = now
+	}
+
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// release returns a reserved token which was never used.
+func (b *TokenBucket) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.tokens++
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+}
@@ -0,0 +1,73 @@
+package weatherkit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTokenBucketBurst(t *testing.T) {
+	bucket := NewTokenBucket(1, 3)
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		err := bucket.Wait(context.TODO())
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Errorf("expected burst to be allowed without waiting, took %s", elapsed)
+	}
+}
+
+func TestTokenBucketWaits(t *testing.T) {
+	bucket := NewTokenBucket(20, 1)
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		err := bucket.Wait(context.TODO())
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("expected to wait for tokens, took %s", elapsed)
+	}
+}
+
+func TestTokenBucketContextDeadline(t *testing.T) {
+	bucket := NewTokenBucket(0.1, 1)
+
+	err := bucket.Wait(context.TODO())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err = bucket.Wait(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got: %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed > 25*time.Millisecond {
+		t.Errorf("expected wait to be refused immediately, took %s", elapsed)
+	}
+}
+
+func TestTokenBucketUnlimited(t *testing.T) {
+	bucket := NewTokenBucket(0, 0)
+
+	for i := 0; i < 100; i++ {
+		err := bucket.Wait(context.TODO())
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+}
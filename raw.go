@@ -0,0 +1,51 @@
+package weatherkit
+
+import (
+	"context"
+	"net/http"
+)
+
+// RawResponse is the HTTP response behind a decoded API response, as sent by the API.
+type RawResponse struct {
+	// The HTTP status code.
+	StatusCode int
+
+	// The response headers, such as Date, Cache-Control and request identifiers.
+	Header http.Header
+
+	// The decompressed JSON body. For non-200 responses, the bounded body kept by RestError.
+	Body []byte
+
+	// The JSON fields of the body not modeled by the response types, recorded when the Client is Strict.
+	// See UnknownFields.
+	UnknownFields []string
+}
+
+type rawResponseKey struct{}
+
+// CaptureRawResponse returns a context which records the response of the API call made with it into raw.
+// raw is left unchanged when the call is served entirely from the Client Cache. When only some of the
+// requested weather data sets are cached, raw holds the response for the data sets requested from the API.
+func CaptureRawResponse(ctx context.Context, raw *RawResponse) context.Context {
+	return context.WithValue(ctx, rawResponseKey{}, raw)
+}
+
+func rawResponseFrom(ctx context.Context) *RawResponse {
+	raw, _ := ctx.Value(rawResponseKey{}).(*RawResponse)
+	return raw
+}
+
+// recordRawResponse starts recording the response into the RawResponse captured by ctx, if any.
+func recordRawResponse(ctx context.Context, response *http.Response) *RawResponse {
+	raw := rawResponseFrom(ctx)
+	if raw == nil {
+		return nil
+	}
+
+	*raw = RawResponse{
+		StatusCode: response.StatusCode,
+		Header:     response.Header.Clone(),
+	}
+
+	return raw
+}
@@ -0,0 +1,140 @@
+package weatherkit
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCaptureRawResponse(t *testing.T) {
+	data, err := ioutil.ReadFile("testdata/full_weather.json")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	for _, encoding := range []string{"", "gzip"} {
+		body := data
+		if len(encoding) > 0 {
+			body = gzipBytes(t, data)
+		}
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Request-Id", "abc")
+			if len(encoding) > 0 {
+				w.Header().Set("Content-Encoding", encoding)
+			}
+			_, _ = w.Write(body)
+		}))
+
+		client := Client{BaseURL: server.URL}
+		raw := RawResponse{}
+
+		response, err := client.Weather(CaptureRawResponse(context.TODO(), &raw), "", WeatherRequest{})
+		server.Close()
+
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		if response.CurrentWeather == nil {
+			t.Errorf("expected the response to be decoded as well")
+		}
+
+		if raw.StatusCode != http.StatusOK || raw.Header.Get("X-Request-Id") != "abc" {
+			t.Errorf("expected the status and headers to be captured, have: %d %v", raw.StatusCode, raw.Header)
+		}
+
+		if !bytes.Equal(raw.Body, data) {
+			t.Errorf("%q encoding: expected the body to be captured as sent, have %d bytes", encoding, len(raw.Body))
+		}
+	}
+}
+
+func TestCaptureRawErrorResponse(t *testing.T) {
+	server, data, err := getMockServerWithFileData("testdata/weather_error.json", http.StatusNotFound)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer server.Close()
+
+	client := Client{BaseURL: server.URL}
+	raw := RawResponse{}
+
+	_, err = client.Alert(CaptureRawResponse(context.TODO(), &raw), "", WeatherAlertRequest{})
+	if err == nil {
+		t.Fatal("expected request to error")
+	}
+
+	if raw.StatusCode != http.StatusNotFound || !bytes.Equal(bytes.TrimSpace(raw.Body), bytes.TrimSpace(data)) {
+		t.Errorf("expected the error response to be captured, have: %d %q", raw.StatusCode, raw.Body)
+	}
+}
+
+func TestCaptureRawResponseCoalesced(t *testing.T) {
+	server, _, release := getBlockingMockServer(t, "testdata/current_weather.json")
+	defer server.Close()
+
+	client := &Client{BaseURL: server.URL, Coalesce: true}
+	request := WeatherRequest{Language: "en", DataSets: DataSets{DataSetCurrentWeather}}
+
+	const callers = 3
+	raws := make([]RawResponse, callers)
+
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			_, err := client.Weather(CaptureRawResponse(context.TODO(), &raws[i]), "", request)
+			if err != nil {
+				t.Error(err.Error())
+			}
+		}(i)
+	}
+
+	waitForWaiters(t, client, request.url(server.URL), callers)
+	close(release)
+	wg.Wait()
+
+	for i, raw := range raws {
+		if raw.StatusCode != http.StatusOK || len(raw.Body) < 1 {
+			t.Errorf("caller %d: expected the shared response to be captured, have: %d %q", i, raw.StatusCode, raw.Body)
+		}
+	}
+}
+
+func TestCaptureRawResponseCacheHit(t *testing.T) {
+	server, _ := getMockDataSetServer(t, time.Now().Add(time.Hour))
+	defer server.Close()
+
+	client := Client{BaseURL: server.URL, Cache: NewMemoryCache(0)}
+	request := WeatherRequest{DataSets: DataSets{DataSetCurrentWeather}}
+
+	raw := RawResponse{}
+
+	_, err := client.Weather(CaptureRawResponse(context.TODO(), &raw), "", request)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if raw.StatusCode != http.StatusOK {
+		t.Errorf("expected the API response to be captured, have: %d", raw.StatusCode)
+	}
+
+	raw = RawResponse{}
+
+	_, err = client.Weather(CaptureRawResponse(context.TODO(), &raw), "", request)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if raw.StatusCode != 0 {
+		t.Errorf("expected nothing to be captured for a cached response, have: %d", raw.StatusCode)
+	}
+}
@@ -0,0 +1,180 @@
+package weatherkit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultTokenRefreshFraction is the fraction of the token lifetime after which WithTokenRefresh renews
+// a token when the configured fraction is not between 0 and 1.
+const DefaultTokenRefreshFraction = 0.5
+
+const (
+	// tokenRefreshRetryDelay is how long the refresher waits to try again after failing to obtain a token.
+	tokenRefreshRetryDelay = 10 * time.Second
+
+	// tokenRefreshMinInterval keeps short lived tokens from being refreshed in a tight loop.
+	tokenRefreshMinInterval = time.Second
+)
+
+// tokenRefresher is a TokenSource which renews its token in the background, after a fraction of its
+// lifetime. Requests are served the renewed token without waiting, and obtain a token synchronously
+// only when there is no valid one, such as before the first refresh or after failed refreshes.
+type tokenRefresher struct {
+	source      TokenSource
+	fraction    float64
+	onError     func(error)
+	retryDelay  time.Duration
+	minInterval time.Duration
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+
+	// mint serializes obtaining tokens, so requests without a valid token wait for a refresh in progress
+	// and concurrent requests obtain a single token.
+	mint sync.Mutex
+
+	cancel    context.CancelFunc
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func newTokenRefresher(source TokenSource, fraction float64, onError func(error)) *tokenRefresher {
+	if fraction <= 0 || fraction >= 1 {
+		fraction = DefaultTokenRefreshFraction
+	}
+
+	return &tokenRefresher{
+		source:      source,
+		fraction:    fraction,
+		onError:     onError,
+		retryDelay:  tokenRefreshRetryDelay,
+		minInterval: tokenRefreshMinInterval,
+		done:        make(chan struct{}),
+	}
+}
+
+// start obtains the first token and keeps renewing it until Close is called.
+func (r *tokenRefresher) start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+
+	go r.run(ctx)
+}
+
+func (r *tokenRefresher) run(ctx context.Context) {
+	defer close(r.done)
+
+	for {
+		wait, ok := r.refresh(ctx)
+		if !ok {
+			return
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// refresh obtains a new token and returns how long to wait before the next refresh. It returns false
+// if the token has no expiration time to schedule a refresh by, or the refresher is closed.
+func (r *tokenRefresher) refresh(ctx context.Context) (time.Duration, bool) {
+	issued := time.Now()
+
+	r.mint.Lock()
+	token, expires, err := r.source.Token(ctx)
+	if err == nil {
+		r.store(token, expires)
+	}
+	r.mint.Unlock()
+
+	if ctx.Err() != nil {
+		return 0, false
+	}
+
+	if err != nil {
+		if r.onError != nil {
+			r.onError(err)
+		}
+		return r.retryDelay, true
+	}
+
+	if expires.IsZero() {
+		return 0, false
+	}
+
+	lifetime := expires.Sub(issued)
+
+	wait := time.Duration(float64(lifetime) * r.fraction)
+	if latest := lifetime - tokenExpiryBuffer; wait > latest {
+		wait = latest
+	}
+
+	if wait < r.minInterval {
+		wait = r.minInterval
+	}
+
+	return wait, true
+}
+
+// Token implements TokenSource.
+func (r *tokenRefresher) Token(ctx context.Context) (string, time.Time, error) {
+	if token, expires, ok := r.current(); ok {
+		return token, expires, nil
+	}
+
+	r.mint.Lock()
+	defer r.mint.Unlock()
+
+	if token, expires, ok := r.current(); ok {
+		return token, expires, nil
+	}
+
+	token, expires, err := r.source.Token(ctx)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	r.store(token, expires)
+
+	return token, expires, nil
+}
+
+// current returns the stored token if it is valid for longer than tokenExpiryBuffer.
+func (r *tokenRefresher) current() (string, time.Time, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.token) > 0 && r.expires.After(time.Now().Add(tokenExpiryBuffer)) {
+		return r.token, r.expires, true
+	}
+
+	return "", time.Time{}, false
+}
+
+func (r *tokenRefresher) store(token string, expires time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.token = token
+	r.expires = expires
+}
+
+// Close stops the background refresh and waits for it to return.
+func (r *tokenRefresher) Close() {
+	r.closeOnce.Do(func() {
+		if r.cancel == nil {
+			return
+		}
+
+		r.cancel()
+		<-r.done
+	})
+}
@@ -0,0 +1,213 @@
+package weatherkit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// sequenceTokenSource returns numbered tokens valid for lifetime, failing while err is set.
+type sequenceTokenSource struct {
+	mu       sync.Mutex
+	calls    int
+	lifetime time.Duration
+	err      error
+}
+
+func (s *sequenceTokenSource) Token(ctx context.Context) (string, time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.calls++
+	if s.err != nil {
+		return "", time.Time{}, s.err
+	}
+
+	return fmt.Sprintf("token-%d", s.calls), time.Now().Add(s.lifetime), nil
+}
+
+func (s *sequenceTokenSource) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.calls
+}
+
+func (s *sequenceTokenSource) fail(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.err = err
+}
+
+// waitFor polls until condition is true, failing the test after a few seconds.
+func waitFor(t *testing.T, description string, condition func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", description)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func newTestTokenRefresher(source TokenSource, fraction float64, onError func(error)) *tokenRefresher {
+	refresher := newTokenRefresher(source, fraction, onError)
+	refresher.retryDelay = 10 * time.Millisecond
+	refresher.minInterval = 10 * time.Millisecond
+
+	return refresher
+}
+
+func TestTokenRefresherRenewsInBackground(t *testing.T) {
+	source := &sequenceTokenSource{lifetime: time.Hour}
+
+	// An hour long token is renewed after about 36 milliseconds.
+	refresher := newTestTokenRefresher(source, 0.00001, nil)
+	refresher.start()
+
+	waitFor(t, "three refreshes", func() bool { return source.count() >= 3 })
+
+	refresher.Close()
+	refresher.Close()
+
+	calls := source.count()
+
+	token, expires, err := refresher.Token(context.TODO())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if want := fmt.Sprintf("token-%d", calls); token != want || time.Until(expires) < 59*time.Minute {
+		t.Errorf("want: %s, have: %s expiring %s", want, token, expires)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+
+	if have := source.count(); have != calls {
+		t.Errorf("expected no token to be obtained after Close, want: %d calls, have: %d", calls, have)
+	}
+}
+
+func TestTokenRefresherFallsBackToSynchronousTokens(t *testing.T) {
+	unavailable := errors.New("unavailable")
+	source := &sequenceTokenSource{lifetime: time.Hour, err: unavailable}
+
+	var mu sync.Mutex
+	reported := []error{}
+
+	refresher := newTestTokenRefresher(source, 0.5, func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		reported = append(reported, err)
+	})
+	refresher.start()
+	defer refresher.Close()
+
+	waitFor(t, "a reported refresh error", func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return len(reported) > 0
+	})
+
+	mu.Lock()
+	first := reported[0]
+	mu.Unlock()
+
+	if !errors.Is(first, unavailable) {
+		t.Errorf("want: %v, have: %v", unavailable, first)
+	}
+
+	_, _, err := refresher.Token(context.TODO())
+	if !errors.Is(err, unavailable) {
+		t.Errorf("expected the synchronous error, got: %v", err)
+	}
+
+	source.fail(nil)
+
+	token, _, err := refresher.Token(context.TODO())
+	if err != nil || len(token) < 1 {
+		t.Errorf("expected a token once the source recovers, have: %q %v", token, err)
+	}
+}
+
+func TestTokenRefresherShortLivedTokens(t *testing.T) {
+	// Tokens expiring within tokenExpiryBuffer are never valid, so every request obtains its own.
+	source := &sequenceTokenSource{lifetime: 30 * time.Second}
+
+	refresher := newTestTokenRefresher(source, 0.5, nil)
+
+	for i := 1; i <= 2; i++ {
+		token, _, err := refresher.Token(context.TODO())
+		if err != nil || token != fmt.Sprintf("token-%d", i) {
+			t.Errorf("want: token-%d, have: %s %v", i, token, err)
+		}
+	}
+}
+
+func TestCredentialedClientTokenRefresh(t *testing.T) {
+	pk, err := createPrivateKeyPEM()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	metrics := newTestMetrics()
+
+	client := NewCredentialedClient(Credentials{
+		KeyID:      "key",
+		TeamID:     "team",
+		ServiceID:  "service",
+		PrivateKey: pk,
+	}, WithBaseURL(server.URL), WithMetrics(metrics), WithTokenRefresh(0.5))
+
+	signings := func() int {
+		return metrics.counter(MetricTokenSignings, Labels{"result": "success"})
+	}
+
+	waitFor(t, "the first background signing", func() bool { return signings() > 0 })
+
+	for i := 0; i < 3; i++ {
+		_, err := client.Weather(context.TODO(), WeatherRequest{})
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+
+	if have := signings(); have != 1 {
+		t.Errorf("expected requests to use the refreshed token, want: %d signings, have: %d", 1, have)
+	}
+
+	err = client.Close()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	_, err = client.Weather(context.TODO(), WeatherRequest{})
+	if err != nil {
+		t.Errorf("expected requests to succeed after Close, got: %v", err)
+	}
+}
+
+func TestCredentialedClientCloseWithoutRefresh(t *testing.T) {
+	client := NewCredentialedClient(Credentials{})
+
+	for i := 0; i < 2; i++ {
+		if err := client.Close(); err != nil {
+			t.Errorf("expected Close to succeed, got: %v", err)
+		}
+	}
+}
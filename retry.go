@@ -0,0 +1,199 @@
+package weatherkit
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy decides whether a failed request is attempted again.
+type RetryPolicy interface {
+	// Retry is called after every unsuccessful attempt, starting with attempt 1.
+	// response is nil when err is a transport error; otherwise it is the non-200 response.
+	// It returns how long to wait before the next attempt and whether to make one at all.
+	Retry(attempt int, response *http.Response, err error) (time.Duration, bool)
+}
+
+// Default values used by ExponentialBackoff when its fields are left empty.
+const (
+	DefaultRetryMaxAttempts = 3
+	DefaultRetryBaseDelay   = 500 * time.Millisecond
+	DefaultRetryMaxDelay    = 30 * time.Second
+)
+
+// DefaultRetryableStatusCodes are the HTTP status codes retried by ExponentialBackoff
+// when RetryableStatusCodes is empty.
+var DefaultRetryableStatusCodes = []int{
+	http.StatusTooManyRequests,
+	http.StatusInternalServerError,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// ExponentialBackoff is a RetryPolicy which doubles the delay after every attempt.
+// A Retry-After header sent along with a retryable response takes precedence over the computed delay.
+// Responses asking to wait longer than MaxDelay are not retried, and are returned as is.
+// Transport errors are retried unless they were caused by the request context.
+// The zero value is ready to use.
+type ExponentialBackoff struct {
+	// The maximum number of attempts, including the first. Defaults to DefaultRetryMaxAttempts.
+	MaxAttempts int
+
+	// The delay before the first retry. Defaults to DefaultRetryBaseDelay.
+	BaseDelay time.Duration
+
+	// The upper bound of the computed delay and of the Retry-After delay. Defaults to DefaultRetryMaxDelay.
+	MaxDelay time.Duration
+
+	// The fraction of the computed delay, from 0 to 1, which is randomly subtracted from it.
+	Jitter float64
+
+	// The HTTP status codes which are retried. Defaults to DefaultRetryableStatusCodes.
+	RetryableStatusCodes []int
+}
+
+// Retry implements RetryPolicy.
+func (b *ExponentialBackoff) Retry(attempt int, response *http.Response, err error) (time.Duration, bool) {
+	if attempt >= b.maxAttempts() {
+		return 0, false
+	}
+
+	if response == nil {
+		if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return 0, false
+		}
+
+		return b.delay(attempt), true
+	}
+
+	if !b.retryable(response.StatusCode) {
+		return 0, false
+	}
+
+	if wait, ok := parseRetryAfter(response.Header.Get("Retry-After"), time.Now()); ok {
+		return wait, wait <= b.maxDelay()
+	}
+
+	return b.delay(attempt), true
+}
+
+func (b *ExponentialBackoff) delay(attempt int) time.Duration {
+	maxDelay := b.maxDelay()
+
+	delay := float64(b.baseDelay()) * math.Pow(2, float64(attempt-1))
+	if delay > float64(maxDelay) {
+		delay = float64(maxDelay)
+	}
+
+	if b.Jitter > 0 {
+		delay -= delay * math.Min(b.Jitter, 1) * rand.Float64()
+	}
+
+	return time.Duration(delay)
+}
+
+func (b *ExponentialBackoff) retryable(statusCode int) bool {
+	codes := b.RetryableStatusCodes
+	if len(codes) < 1 {
+		codes = DefaultRetryableStatusCodes
+	}
+
+	for _, code := range codes {
+		if code == statusCode {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (b *ExponentialBackoff) maxAttempts() int {
+	if b.MaxAttempts > 0 {
+		return b.MaxAttempts
+	}
+
+	return DefaultRetryMaxAttempts
+}
+
+func (b *ExponentialBackoff) baseDelay() time.Duration {
+	if b.BaseDelay > 0 {
+		return b.BaseDelay
+	}
+
+	return DefaultRetryBaseDelay
+}
+
+func (b *ExponentialBackoff) maxDelay() time.Duration {
+	if b.MaxDelay > 0 {
+		return b.MaxDelay
+	}
+
+	return DefaultRetryMaxDelay
+}
+
+// parseRetryAfter parses a Retry-After header value in either delay-seconds or HTTP-date form.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if len(value) < 1 {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+
+	wait := date.Sub(now)
+	if wait < 0 {
+		wait = 0
+	}
+
+	return wait, true
+}
+
+// sleep waits for the delay to elapse or the context to be done, whichever happens first.
+func sleep(ctx context.Context, delay time.Duration) error {
+	if delay <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// fitsDeadline reports whether waiting for delay leaves the context deadline intact.
+func fitsDeadline(ctx context.Context, delay time.Duration) bool {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return true
+	}
+
+	return time.Now().Add(delay).Before(deadline)
+}
+
+// discard drains and closes a response body so the underlying connection may be reused.
+func discard(response *http.Response) {
+	_, _ = io.Copy(ioutil.Discard, io.LimitReader(response.Body, 64<<10))
+	response.Body.Close()
+}
@@ -0,0 +1,242 @@
+package weatherkit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryUntilSuccess(t *testing.T) {
+	var calls int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintln(w, `{"serviceName":"Weather"}`)
+	}))
+	defer server.Close()
+
+	client := Client{
+		BaseURL:     server.URL,
+		RetryPolicy: &ExponentialBackoff{BaseDelay: time.Millisecond},
+	}
+
+	response, err := client.Attribution(context.TODO(), AttributionRequest{Language: "en"})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if response.ServiceName != "Weather" {
+		t.Errorf("want: %s, have: %s", "Weather", response.ServiceName)
+	}
+
+	if calls != 3 {
+		t.Errorf("want: %d calls, have: %d", 3, calls)
+	}
+}
+
+func TestRetryGivesUpAfterMaxAttempts(t *testing.T) {
+	var calls int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusTooManyRequests)
+		fmt.Fprintln(w, `{"reason":"RATE_LIMIT"}`)
+	}))
+	defer server.Close()
+
+	client := Client{
+		BaseURL:     server.URL,
+		RetryPolicy: &ExponentialBackoff{MaxAttempts: 4, BaseDelay: time.Millisecond},
+	}
+
+	_, err := client.Attribution(context.TODO(), AttributionRequest{Language: "en"})
+
+	restError := &RestError{}
+	if !errors.As(err, &restError) {
+		t.Fatalf("expected a RestError, got: %v", err)
+	}
+
+	if restError.Response.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("want: %d, have: %d", http.StatusTooManyRequests, restError.Response.StatusCode)
+	}
+
+	if calls != 4 {
+		t.Errorf("want: %d calls, have: %d", 4, calls)
+	}
+}
+
+func TestRetrySkipsNonRetryableStatus(t *testing.T) {
+	var calls int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprintln(w, `{}`)
+	}))
+	defer server.Close()
+
+	client := Client{
+		BaseURL:     server.URL,
+		RetryPolicy: &ExponentialBackoff{BaseDelay: time.Millisecond},
+	}
+
+	_, err := client.Attribution(context.TODO(), AttributionRequest{Language: "en"})
+	if err == nil {
+		t.Fatal("expected request to error")
+	}
+
+	if calls != 1 {
+		t.Errorf("want: %d calls, have: %d", 1, calls)
+	}
+}
+
+func TestRetryRespectsContextDeadline(t *testing.T) {
+	var calls int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprintln(w, `{}`)
+	}))
+	defer server.Close()
+
+	client := Client{
+		BaseURL:     server.URL,
+		RetryPolicy: &ExponentialBackoff{},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	_, err := client.Attribution(ctx, AttributionRequest{Language: "en"})
+	if err == nil {
+		t.Fatal("expected request to error")
+	}
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected the retry to be abandoned immediately, took %s", elapsed)
+	}
+
+	if calls != 1 {
+		t.Errorf("want: %d calls, have: %d", 1, calls)
+	}
+}
+
+func TestRetryAfterBeyondMaxDelayWithoutDeadline(t *testing.T) {
+	var calls int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Retry-After", "86400")
+		w.WriteHeader(http.StatusTooManyRequests)
+		fmt.Fprintln(w, `{}`)
+	}))
+	defer server.Close()
+
+	client := Client{
+		BaseURL:     server.URL,
+		RetryPolicy: &ExponentialBackoff{},
+	}
+
+	start := time.Now()
+	_, err := client.Attribution(context.Background(), AttributionRequest{Language: "en"})
+	if !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("expected %v, got: %v", ErrRateLimited, err)
+	}
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected the retry to be abandoned immediately, took %s", elapsed)
+	}
+
+	if wait, ok := RetryAfter(err); !ok || wait != 24*time.Hour {
+		t.Errorf("expected the Retry-After delay to be left to the caller, have: %s", wait)
+	}
+
+	if calls != 1 {
+		t.Errorf("want: %d calls, have: %d", 1, calls)
+	}
+}
+
+func TestExponentialBackoffDelay(t *testing.T) {
+	policy := &ExponentialBackoff{
+		MaxAttempts: 10,
+		BaseDelay:   100 * time.Millisecond,
+		MaxDelay:    time.Second,
+	}
+
+	response := &http.Response{StatusCode: http.StatusBadGateway, Header: http.Header{}}
+
+	for attempt, want := range map[int]time.Duration{
+		1: 100 * time.Millisecond,
+		2: 200 * time.Millisecond,
+		3: 400 * time.Millisecond,
+		4: 800 * time.Millisecond,
+		5: time.Second,
+	} {
+		have, retry := policy.Retry(attempt, response, nil)
+		if !retry {
+			t.Errorf("expected attempt %d to be retried", attempt)
+		}
+
+		if have != want {
+			t.Errorf("attempt %d want: %s, have: %s", attempt, want, have)
+		}
+	}
+
+	response.Header.Set("Retry-After", "1")
+	have, retry := policy.Retry(1, response, nil)
+	if !retry || have != time.Second {
+		t.Errorf("want: %s, have: %s", time.Second, have)
+	}
+
+	for _, value := range []string{"7", "86400", time.Now().Add(48 * time.Hour).UTC().Format(http.TimeFormat)} {
+		response.Header.Set("Retry-After", value)
+		if _, retry := policy.Retry(1, response, nil); retry {
+			t.Errorf("expected a Retry-After of %q beyond MaxDelay not to be retried", value)
+		}
+	}
+
+	if _, retry := policy.Retry(1, nil, context.Canceled); retry {
+		t.Errorf("expected context errors not to be retried")
+	}
+
+	if _, retry := policy.Retry(1, nil, errors.New("connection reset")); !retry {
+		t.Errorf("expected transport errors to be retried")
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2022, 7, 10, 6, 14, 11, 0, time.UTC)
+
+	tests := []struct {
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{"", 0, false},
+		{"120", 2 * time.Minute, true},
+		{"-1", 0, false},
+		{"Sun, 10 Jul 2022 06:15:11 GMT", time.Minute, true},
+		{"Sun, 10 Jul 2022 06:13:11 GMT", 0, true},
+		{"soon", 0, false},
+	}
+
+	for _, test := range tests {
+		have, ok := parseRetryAfter(test.value, now)
+		if have != test.want || ok != test.ok {
+			t.Errorf("%q want: %s %t, have: %s %t", test.value, test.want, test.ok, have, ok)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package weatherkit
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// es256KeySize is the size in bytes of each of the r and s values of an ES256 signature.
+const es256KeySize = 32
+
+// NewSignerTokenSource creates a SigningTokenSource which signs tokens valid for validFor with signer instead of
+// the PrivateKey of the credentials, such as a key held by an HSM or a cloud KMS, so the key itself never has to
+// be loaded. The public key of the signer must be an ECDSA key on the P-256 curve, and Sign must return an
+// ASN.1 DER encoded signature as crypto/ecdsa does. The default duration is 10 minutes.
+func NewSignerTokenSource(credentials Credentials, signer crypto.Signer, validFor time.Duration) (*SigningTokenSource, error) {
+	err := credentials.validate(defaultTokenDuration)
+	if err != nil {
+		return nil, err
+	}
+
+	err = validateSigner(signer)
+	if err != nil {
+		return nil, err
+	}
+
+	if validFor == 0 {
+		validFor = defaultTokenDuration
+	}
+
+	return &SigningTokenSource{
+		credentials: credentials,
+		validFor:    validFor,
+		signer:      signer,
+	}, nil
+}
+
+func validateSigner(signer crypto.Signer) error {
+	if signer == nil {
+		return errors.New("signer may not be nil")
+	}
+
+	public, ok := signer.Public().(*ecdsa.PublicKey)
+	if !ok {
+		return fmt.Errorf("signer must have an ECDSA public key, have: %T", signer.Public())
+	}
+
+	if public.Curve != elliptic.P256() {
+		return fmt.Errorf("signer must use the P-256 curve, have: %s", public.Curve.Params().Name)
+	}
+
+	return nil
+}
+
+// signES256 signs the SHA-256 digest of the signing string with signer,
+// returning the raw r||s signature required by JWS.
+func signES256(signer crypto.Signer, signingString string) ([]byte, error) {
+	digest := sha256.Sum256([]byte(signingString))
+
+	signature, err := signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+	if err != nil {
+		return nil, err
+	}
+
+	return rawSignature(signature)
+}
+
+// rawSignature converts an ASN.1 DER encoded ECDSA signature into the fixed size r||s form.
+func rawSignature(der []byte) ([]byte, error) {
+	signature := struct {
+		R, S *big.Int
+	}{}
+
+	rest, err := asn1.Unmarshal(der, &signature)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ECDSA signature. %s", err)
+	}
+
+	if len(rest) > 0 {
+		return nil, errors.New("invalid ECDSA signature. trailing data after the signature")
+	}
+
+	if signature.R.Sign() <= 0 || signature.S.Sign() <= 0 ||
+		signature.R.BitLen() > es256KeySize*8 || signature.S.BitLen() > es256KeySize*8 {
+		return nil, errors.New("invalid ECDSA signature. r and s must be positive 256 bit values")
+	}
+
+	raw := make([]byte, 2*es256KeySize)
+	signature.R.FillBytes(raw[:es256KeySize])
+	signature.S.FillBytes(raw[es256KeySize:])
+
+	return raw, nil
+}
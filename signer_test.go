@@ -0,0 +1,200 @@
+package weatherkit
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/asn1"
+	"errors"
+	"io"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// softwareSigner stands in for an HSM or KMS signer: it exposes the key only through crypto.Signer.
+type softwareSigner struct {
+	key   *ecdsa.PrivateKey
+	calls int
+	sign  func(digest []byte) ([]byte, error)
+}
+
+func (s *softwareSigner) Public() crypto.PublicKey {
+	return &s.key.PublicKey
+}
+
+func (s *softwareSigner) Sign(random io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	s.calls++
+	if s.sign != nil {
+		return s.sign(digest)
+	}
+
+	return ecdsa.SignASN1(random, s.key, digest)
+}
+
+func newSoftwareSigner(t testing.TB, curve elliptic.Curve) *softwareSigner {
+	key, err := ecdsa.GenerateKey(curve, rand.Reader)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	return &softwareSigner{key: key}
+}
+
+func TestSignerTokenSource(t *testing.T) {
+	signer := newSoftwareSigner(t, elliptic.P256())
+
+	source, err := NewSignerTokenSource(Credentials{KeyID: "key", TeamID: "team", ServiceID: "service"}, signer, time.Minute)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	// Signatures with short r or s values must still be padded to 32 bytes each.
+	for i := 0; i < 64; i++ {
+		signed, _, err := source.Token(context.TODO())
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		signature, err := jwt.DecodeSegment(signed[strings.LastIndex(signed, ".")+1:])
+		if err != nil || len(signature) != 64 {
+			t.Fatalf("expected a 64 byte r||s signature, have: %d bytes %v", len(signature), err)
+		}
+
+		parsed, err := jwt.Parse(signed, func(token *jwt.Token) (interface{}, error) {
+			return &signer.key.PublicKey, nil
+		})
+		if err != nil || parsed.Method != jwt.SigningMethodES256 {
+			t.Fatalf("expected the token to verify as ES256, got: %v", err)
+		}
+	}
+
+	if signer.calls != 64 {
+		t.Errorf("want: %d calls to the signer, have: %d", 64, signer.calls)
+	}
+}
+
+func TestNewSignerTokenSourceRejectsInvalidSigners(t *testing.T) {
+	_, ed25519Key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	tests := map[string]struct {
+		signer crypto.Signer
+		want   string
+	}{
+		"nil":     {nil, "may not be nil"},
+		"p-384":   {newSoftwareSigner(t, elliptic.P384()), "P-256"},
+		"ed25519": {ed25519Key, "ECDSA public key"},
+	}
+
+	credentials := Credentials{KeyID: "key", TeamID: "team", ServiceID: "service"}
+
+	for name, test := range tests {
+		_, err := NewSignerTokenSource(credentials, test.signer, 0)
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%s: expected an error containing %q, got: %v", name, test.want, err)
+		}
+	}
+
+	_, err = NewSignerTokenSource(Credentials{KeyID: "key", ServiceID: "service"}, newSoftwareSigner(t, elliptic.P256()), 0)
+	if err == nil || !strings.Contains(err.Error(), "team ID may not be empty") {
+		t.Errorf("expected the identifiers to be validated, got: %v", err)
+	}
+}
+
+func TestSignerTokenSourceErrors(t *testing.T) {
+	failing := newSoftwareSigner(t, elliptic.P256())
+	failing.sign = func(digest []byte) ([]byte, error) {
+		return nil, errors.New("kms unavailable")
+	}
+
+	raw := newSoftwareSigner(t, elliptic.P256())
+	raw.sign = func(digest []byte) ([]byte, error) {
+		return make([]byte, 64), nil
+	}
+
+	tests := map[string]struct {
+		signer *softwareSigner
+		want   string
+	}{
+		"failing": {failing, "kms unavailable"},
+		"not der": {raw, "invalid ECDSA signature"},
+	}
+
+	for name, test := range tests {
+		source, err := NewSignerTokenSource(Credentials{KeyID: "key", TeamID: "team", ServiceID: "service"}, test.signer, 0)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		_, _, err = source.Token(context.TODO())
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%s: expected an error containing %q, got: %v", name, test.want, err)
+		}
+	}
+}
+
+func TestRawSignature(t *testing.T) {
+	der := func(r *big.Int, s *big.Int) []byte {
+		data, err := asn1.Marshal(struct{ R, S *big.Int }{r, s})
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		return data
+	}
+
+	raw, err := rawSignature(der(big.NewInt(1), big.NewInt(2)))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(raw) != 64 || raw[31] != 1 || raw[63] != 2 {
+		t.Errorf("expected r and s to be left padded, have: %x", raw)
+	}
+
+	tooLarge := new(big.Int).Lsh(big.NewInt(1), 256)
+
+	invalid := map[string][]byte{
+		"too large": der(tooLarge, big.NewInt(1)),
+		"negative":  der(big.NewInt(-1), big.NewInt(1)),
+		"trailing":  append(der(big.NewInt(1), big.NewInt(2)), 0),
+		"garbage":   []byte("signature"),
+	}
+
+	for name, data := range invalid {
+		if _, err := rawSignature(data); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestCredentialedClientWithSigner(t *testing.T) {
+	signer := newSoftwareSigner(t, elliptic.P256())
+
+	credentials := Credentials{KeyID: "key", TeamID: "team", ServiceID: "service"}
+
+	token, err := NewCredentialedClient(credentials, WithSigner(signer)).getToken(context.TODO())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	_, err = jwt.Parse(token, func(token *jwt.Token) (interface{}, error) {
+		return &signer.key.PublicKey, nil
+	})
+	if err != nil {
+		t.Errorf("expected the token to verify, got: %v", err)
+	}
+
+	_, err = NewCredentialedClient(credentials, WithSigner(newSoftwareSigner(t, elliptic.P384()))).getToken(context.TODO())
+	if !errors.Is(err, ErrToken) || !strings.Contains(err.Error(), "P-256") {
+		t.Errorf("expected an unsuitable signer to be reported, got: %v", err)
+	}
+}
@@ -0,0 +1,190 @@
+package weatherkit
+
+import (
+	"context"
+	"crypto"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// TokenSource provides JWT developer tokens.
+// Implementations must be safe for concurrent use.
+type TokenSource interface {
+	// Token returns a developer token along with its expiration time.
+	// The expiration time is zero when it is unknown.
+	Token(ctx context.Context) (string, time.Time, error)
+}
+
+// tokenExpiryBuffer is how long before its expiration a token is replaced, to allow for request and response time.
+const tokenExpiryBuffer = time.Minute
+
+// tokenSourceFunc adapts a function to a TokenSource.
+type tokenSourceFunc func(ctx context.Context) (string, time.Time, error)
+
+func (f tokenSourceFunc) Token(ctx context.Context) (string, time.Time, error) {
+	return f(ctx)
+}
+
+// NewSigningTokenSource creates a TokenSource which signs a new token valid for validFor with the credentials
+// on every call. The default duration is 10 minutes. The private key is parsed here, and parsed again only if
+// the PrivateKey bytes change.
+func NewSigningTokenSource(credentials Credentials, validFor time.Duration) *SigningTokenSource {
+	if validFor == 0 {
+		validFor = defaultTokenDuration
+	}
+
+	if credentials.parsed == nil {
+		credentials.parsed = &parsedKey{}
+	}
+
+	// A key which fails to parse is reported by Token.
+	_, _ = credentials.privateKey()
+
+	return &SigningTokenSource{
+		credentials: credentials,
+		validFor:    validFor,
+	}
+}
+
+// SigningTokenSource is a TokenSource signing tokens locally with a private key.
+// Construct with NewSigningTokenSource or NewSignerTokenSource.
+type SigningTokenSource struct {
+	credentials Credentials
+	validFor    time.Duration
+	signer      crypto.Signer
+}
+
+// Token implements TokenSource.
+func (s *SigningTokenSource) Token(ctx context.Context) (string, time.Time, error) {
+	return s.credentials.signedJWT(s.validFor, s.signer)
+}
+
+// NewStaticTokenSource creates a TokenSource which always returns token.
+// The expiration time is read from the exp claim of the token, if any.
+func NewStaticTokenSource(token string) *StaticTokenSource {
+	return &StaticTokenSource{
+		token:   token,
+		expires: tokenExpiration(token),
+	}
+}
+
+// StaticTokenSource is a TokenSource returning the same token, such as one minted by another service.
+// Construct with NewStaticTokenSource.
+type StaticTokenSource struct {
+	token   string
+	expires time.Time
+}
+
+// Token implements TokenSource.
+func (s *StaticTokenSource) Token(ctx context.Context) (string, time.Time, error) {
+	if len(s.token) < 1 {
+		return "", time.Time{}, errors.New("static token may not be empty")
+	}
+
+	return s.token, s.expires, nil
+}
+
+// NewFileTokenSource creates a TokenSource which reads the token from the file at path, such as one
+// kept up to date by a sidecar. The file is read again whenever its modification time or size changes.
+// The expiration time is read from the exp claim of the token, if any.
+func NewFileTokenSource(path string) *FileTokenSource {
+	return &FileTokenSource{path: path}
+}
+
+// FileTokenSource is a TokenSource reading the token from a file.
+// Construct with NewFileTokenSource.
+type FileTokenSource struct {
+	path    string
+	mu      sync.Mutex
+	modTime time.Time
+	size    int64
+	token   string
+	expires time.Time
+}
+
+// Token implements TokenSource.
+func (s *FileTokenSource) Token(ctx context.Context) (string, time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to read token file. %s", err)
+	}
+
+	if len(s.token) > 0 && info.ModTime().Equal(s.modTime) && info.Size() == s.size {
+		return s.token, s.expires, nil
+	}
+
+	data, err := ioutil.ReadFile(s.path)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to read token file. %s", err)
+	}
+
+	token := strings.TrimSpace(string(data))
+	if len(token) < 1 {
+		return "", time.Time{}, fmt.Errorf("token file %s is empty", s.path)
+	}
+
+	s.modTime = info.ModTime()
+	s.size = info.Size()
+	s.token = token
+	s.expires = tokenExpiration(token)
+
+	return s.token, s.expires, nil
+}
+
+// NewCachingTokenSource creates a TokenSource which reuses the tokens of source until a minute before they expire.
+// Tokens without an expiration time are not cached.
+func NewCachingTokenSource(source TokenSource) *CachingTokenSource {
+	return &CachingTokenSource{source: source}
+}
+
+// CachingTokenSource is a TokenSource caching the tokens of another TokenSource.
+// Construct with NewCachingTokenSource.
+type CachingTokenSource struct {
+	source  TokenSource
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+// Token implements TokenSource.
+func (s *CachingTokenSource) Token(ctx context.Context) (string, time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.token) > 0 && s.expires.After(time.Now().Add(tokenExpiryBuffer)) {
+		return s.token, s.expires, nil
+	}
+
+	token, expires, err := s.source.Token(ctx)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	s.token = token
+	s.expires = expires
+
+	return token, expires, nil
+}
+
+// tokenExpiration returns the time of the exp claim of the token, without verifying its signature.
+// It returns the zero time if the token has no such claim or cannot be parsed.
+func tokenExpiration(token string) time.Time {
+	claims := jwt.RegisteredClaims{}
+
+	_, _, err := jwt.NewParser().ParseUnverified(token, &claims)
+	if err != nil || claims.ExpiresAt == nil {
+		return time.Time{}
+	}
+
+	return claims.ExpiresAt.Time
+}
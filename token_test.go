@@ -0,0 +1,216 @@
+package weatherkit
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+func TestSigningTokenSource(t *testing.T) {
+	pk, err := createPrivateKeyPEM()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	source := NewSigningTokenSource(Credentials{KeyID: "key", TeamID: "team", ServiceID: "service", PrivateKey: pk}, time.Minute)
+
+	token, expires, err := source.Token(context.TODO())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if have := tokenExpiration(token); !have.Equal(expires.Truncate(time.Second)) {
+		t.Errorf("want: %s, have: %s", expires, have)
+	}
+
+	if until := time.Until(expires); until <= 0 || until > time.Minute {
+		t.Errorf("expected the token to expire within a minute, have: %s", until)
+	}
+}
+
+func TestStaticTokenSource(t *testing.T) {
+	exp := time.Now().Add(time.Hour).Truncate(time.Second)
+
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(exp)}).SignedString([]byte("secret"))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	tests := map[string]time.Time{
+		signed:   exp,
+		"opaque": {},
+	}
+
+	for token, want := range tests {
+		have, expires, err := NewStaticTokenSource(token).Token(context.TODO())
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		if have != token || !expires.Equal(want) {
+			t.Errorf("want: %s %s, have: %s %s", token, want, have, expires)
+		}
+	}
+
+	_, _, err = NewStaticTokenSource("").Token(context.TODO())
+	if err == nil {
+		t.Errorf("expected an empty token to error")
+	}
+}
+
+func TestFileTokenSource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+
+	err := ioutil.WriteFile(path, []byte("first\n"), 0600)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	source := NewFileTokenSource(path)
+
+	token, _, err := source.Token(context.TODO())
+	if err != nil || token != "first" {
+		t.Fatalf("want: %s, have: %s %v", "first", token, err)
+	}
+
+	err = ioutil.WriteFile(path, []byte("second"), 0600)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	later := time.Now().Add(time.Minute)
+	err = os.Chtimes(path, later, later)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	token, _, err = source.Token(context.TODO())
+	if err != nil || token != "second" {
+		t.Errorf("expected the changed file to be read again, want: %s, have: %s %v", "second", token, err)
+	}
+
+	err = ioutil.WriteFile(path, []byte(" \n"), 0600)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	_, _, err = source.Token(context.TODO())
+	if err == nil || !strings.Contains(err.Error(), "empty") {
+		t.Errorf("expected an empty file to error, got: %v", err)
+	}
+
+	_, _, err = NewFileTokenSource(filepath.Join(t.TempDir(), "missing")).Token(context.TODO())
+	if err == nil {
+		t.Errorf("expected a missing file to error")
+	}
+}
+
+func TestCachingTokenSource(t *testing.T) {
+	tests := map[string]struct {
+		expires time.Time
+		calls   int
+	}{
+		"fresh":    {time.Now().Add(time.Hour), 1},
+		"expiring": {time.Now().Add(30 * time.Second), 3},
+		"unknown":  {time.Time{}, 3},
+	}
+
+	for name, test := range tests {
+		source := &countingTokenSource{expires: test.expires}
+		caching := NewCachingTokenSource(source)
+
+		for i := 0; i < 3; i++ {
+			token, _, err := caching.Token(context.TODO())
+			if err != nil || token != "token" {
+				t.Fatalf("%s: want: %s, have: %s %v", name, "token", token, err)
+			}
+		}
+
+		if source.calls != test.calls {
+			t.Errorf("%s: want: %d calls, have: %d", name, test.calls, source.calls)
+		}
+	}
+
+	source := &countingTokenSource{expires: time.Now().Add(time.Hour), err: errors.New("unavailable")}
+	caching := NewCachingTokenSource(source)
+
+	for i := 0; i < 2; i++ {
+		if _, _, err := caching.Token(context.TODO()); err == nil {
+			t.Fatal("expected the error of the source")
+		}
+	}
+
+	if source.calls != 2 {
+		t.Errorf("expected errors not to be cached, want: %d calls, have: %d", 2, source.calls)
+	}
+}
+
+func TestCredentialedClientTokenSource(t *testing.T) {
+	var mu sync.Mutex
+	authorizations := []string{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		authorizations = append(authorizations, r.Header.Get("Authorization"))
+		mu.Unlock()
+
+		_, _ = w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	source := &countingTokenSource{expires: time.Now().Add(time.Hour)}
+	client := NewCredentialedClient(Credentials{}, WithBaseURL(server.URL), WithTokenSource(source))
+
+	for i := 0; i < 2; i++ {
+		_, err := client.Weather(context.TODO(), WeatherRequest{})
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+
+	if source.calls != 2 {
+		t.Errorf("expected the source to be called for every request, want: %d, have: %d", 2, source.calls)
+	}
+
+	for _, authorization := range authorizations {
+		if authorization != "Bearer token" {
+			t.Errorf("want: %s, have: %s", "Bearer token", authorization)
+		}
+	}
+
+	source.err = errors.New("unavailable")
+
+	_, err := client.Weather(context.TODO(), WeatherRequest{})
+	if !errors.Is(err, ErrToken) {
+		t.Errorf("expected %v, got: %v", ErrToken, err)
+	}
+}
+
+type countingTokenSource struct {
+	mu      sync.Mutex
+	calls   int
+	expires time.Time
+	err     error
+}
+
+func (s *countingTokenSource) Token(ctx context.Context) (string, time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.calls++
+	if s.err != nil {
+		return "", time.Time{}, s.err
+	}
+
+	return "token", s.expires, nil
+}
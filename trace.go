@@ -0,0 +1,135 @@
+package weatherkit
+
+import (
+	"context"
+	"encoding/hex"
+	"math"
+)
+
+// Tracer starts spans around the work done by the clients, so any tracing backend can be plugged in.
+type Tracer interface {
+	// Start begins a span named name as a child of the span in ctx, if any.
+	// The returned context carries the new span.
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is a single traced operation started by a Tracer.
+type Span interface {
+	// SetAttribute annotates the span.
+	SetAttribute(key string, value interface{})
+
+	// RecordError marks the span as failed.
+	RecordError(err error)
+
+	// End completes the span.
+	End()
+
+	// TraceParent returns the W3C traceparent header value identifying the span,
+	// or an empty string if the span should not be propagated.
+	TraceParent() string
+}
+
+// Names of the spans started by the clients.
+const (
+	// Spans a single API call, including retries.
+	SpanRequest = "weatherkit.request"
+
+	// Spans a single HTTP attempt of an API call. Retries produce one span per attempt.
+	SpanAttempt = "weatherkit.attempt"
+
+	// Spans the signing of a developer token, nested in the SpanRequest span of the call needing it.
+	// There is no signing span when the token is served from the token cache.
+	SpanSignToken = "weatherkit.sign_token"
+)
+
+// Coordinates are rounded to this many decimal places, about a kilometer, before being attached to spans.
+const spanCoordinatePrecision = 2
+
+// FormatTraceParent formats a W3C traceparent header value for Span implementations.
+func FormatTraceParent(traceID [16]byte, spanID [8]byte, sampled bool) string {
+	flags := "00"
+	if sampled {
+		flags = "01"
+	}
+
+	return "00-" + hex.EncodeToString(traceID[:]) + "-" + hex.EncodeToString(spanID[:]) + "-" + flags
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttribute(key string, value interface{}) {}
+func (noopSpan) RecordError(err error)                      {}
+func (noopSpan) End()                                       {}
+func (noopSpan) TraceParent() string                        { return "" }
+
+func startSpan(tracer Tracer, ctx context.Context, name string) (context.Context, Span) {
+	if tracer == nil {
+		return ctx, noopSpan{}
+	}
+
+	return tracer.Start(ctx, name)
+}
+
+type requestSpanKey struct{}
+
+// withRequestSpan returns a context whose API call annotates span instead of starting its own request span,
+// so a CredentialedClient can obtain the token within the request span. A nil span clears it.
+func withRequestSpan(ctx context.Context, span Span) context.Context {
+	return context.WithValue(ctx, requestSpanKey{}, span)
+}
+
+// startRequestSpan starts the request span of an API call, unless the context carries one already.
+// The returned function ends the span if it was started here.
+func startRequestSpan(tracer Tracer, ctx context.Context) (context.Context, Span, func()) {
+	if span, ok := ctx.Value(requestSpanKey{}).(Span); ok {
+		return withRequestSpan(ctx, nil), span, func() {}
+	}
+
+	ctx, span := startSpan(tracer, ctx, SpanRequest)
+	return ctx, span, span.End
+}
+
+// setRequestAttributes annotates the span with the endpoint and the identifying parts of the typed request.
+func setRequestAttributes(span Span, call *Call) {
+	span.SetAttribute("endpoint", string(call.Endpoint))
+
+	switch request := call.Request.(type) {
+	case WeatherRequest:
+		span.SetAttribute("latitude", roundCoordinate(request.Latitude))
+		span.SetAttribute("longitude", roundCoordinate(request.Longitude))
+		span.SetAttribute("data_sets", request.DataSets.String())
+	case AvailabilityRequest:
+		span.SetAttribute("latitude", roundCoordinate(request.Latitude))
+		span.SetAttribute("longitude", roundCoordinate(request.Longitude))
+	case WeatherAlertRequest:
+		span.SetAttribute("alert_id", request.ID)
+	}
+}
+
+// setResponseAttributes annotates the span with the outcome of the call.
+func setResponseAttributes(span Span, call *Call, err error) {
+	if call.HTTPResponse != nil {
+		span.SetAttribute("status", call.HTTPResponse.StatusCode)
+
+		if body, ok := call.HTTPResponse.Body.(*countingReadCloser); ok {
+			span.SetAttribute("bytes", body.n)
+		}
+	}
+
+	if err != nil {
+		span.RecordError(err)
+	}
+}
+
+func roundCoordinate(value float64) float64 {
+	scale := math.Pow(10, spanCoordinatePrecision)
+	return math.Round(value*scale) / scale
+}
+
+func (c *CredentialedClient) tracer() Tracer {
+	if c.options.tracer != nil {
+		return c.options.tracer
+	}
+
+	return c.options.client.Tracer
+}
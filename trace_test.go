@@ -0,0 +1,274 @@
+package weatherkit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type testSpanKey struct{}
+
+type testSpan struct {
+	tracer     *testTracer
+	id         int
+	name       string
+	parent     *testSpan
+	attributes map[string]interface{}
+	errors     []error
+	ended      bool
+}
+
+func (s *testSpan) SetAttribute(key string, value interface{}) {
+	s.tracer.mu.Lock()
+	defer s.tracer.mu.Unlock()
+
+	s.attributes[key] = value
+}
+
+func (s *testSpan) RecordError(err error) {
+	s.tracer.mu.Lock()
+	defer s.tracer.mu.Unlock()
+
+	s.errors = append(s.errors, err)
+}
+
+func (s *testSpan) End() {
+	s.tracer.mu.Lock()
+	defer s.tracer.mu.Unlock()
+
+	s.ended = true
+}
+
+func (s *testSpan) TraceParent() string {
+	return FormatTraceParent([16]byte{1}, [8]byte{byte(s.id)}, true)
+}
+
+type testTracer struct {
+	mu    sync.Mutex
+	spans []*testSpan
+}
+
+func (tr *testTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	parent, _ := ctx.Value(testSpanKey{}).(*testSpan)
+
+	span := &testSpan{
+		tracer:     tr,
+		id:         len(tr.spans) + 1,
+		name:       name,
+		parent:     parent,
+		attributes: map[string]interface{}{},
+	}
+	tr.spans = append(tr.spans, span)
+
+	return context.WithValue(ctx, testSpanKey{}, span), span
+}
+
+func (tr *testTracer) named(name string) []*testSpan {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	spans := []*testSpan{}
+	for _, span := range tr.spans {
+		if span.name == name {
+			spans = append(spans, span)
+		}
+	}
+
+	return spans
+}
+
+func TestTracerSpans(t *testing.T) {
+	var calls int32
+	traceParents := make(chan string, 2)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceParents <- r.Header.Get("traceparent")
+
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintln(w, `{}`)
+	}))
+	defer server.Close()
+
+	pk, err := createPrivateKeyPEM()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tracer := &testTracer{}
+
+	client := NewCredentialedClient(Credentials{
+		KeyID:      "key",
+		TeamID:     "team",
+		ServiceID:  "service",
+		PrivateKey: pk,
+	}, WithTracer(tracer), WithClient(&Client{
+		BaseURL:               server.URL,
+		Tracer:                tracer,
+		PropagateTraceContext: true,
+		RetryPolicy:           &ExponentialBackoff{BaseDelay: time.Millisecond},
+	}))
+
+	ctx, root := tracer.Start(context.Background(), "root")
+
+	_, err = client.Weather(ctx, WeatherRequest{
+		Latitude:  40.71278,
+		Longitude: -74.00597,
+		DataSets:  DataSets{DataSetCurrentWeather, DataSetForecastHourly},
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	requests := tracer.named(SpanRequest)
+	if len(requests) != 1 || requests[0].parent != root {
+		t.Fatalf("expected a request span below the root span, have: %+v", requests)
+	}
+
+	request := requests[0]
+
+	signing := tracer.named(SpanSignToken)
+	if len(signing) != 1 || signing[0].parent != request {
+		t.Fatalf("expected a token signing span below the request span, have: %+v", signing)
+	}
+	want := map[string]interface{}{
+		"endpoint":  "weather",
+		"latitude":  40.71,
+		"longitude": -74.01,
+		"data_sets": "currentWeather,forecastHourly",
+		"status":    http.StatusOK,
+		"bytes":     int64(3),
+	}
+
+	for key, value := range want {
+		if request.attributes[key] != value {
+			t.Errorf("%s want: %v, have: %v", key, value, request.attributes[key])
+		}
+	}
+
+	attempts := tracer.named(SpanAttempt)
+	if len(attempts) != 2 {
+		t.Fatalf("want: %d attempt spans, have: %d", 2, len(attempts))
+	}
+
+	for i, attempt := range attempts {
+		if attempt.parent != request {
+			t.Errorf("expected attempt span %d below the request span", i)
+		}
+
+		if attempt.attributes["attempt"] != i+1 {
+			t.Errorf("want: attempt %d, have: %v", i+1, attempt.attributes["attempt"])
+		}
+
+		if have := <-traceParents; have != attempt.TraceParent() {
+			t.Errorf("want: traceparent %s, have: %s", attempt.TraceParent(), have)
+		}
+
+		if !attempt.ended {
+			t.Errorf("expected attempt span %d to be ended", i)
+		}
+	}
+
+	if attempts[0].attributes["status"] != http.StatusServiceUnavailable {
+		t.Errorf("want: %d, have: %v", http.StatusServiceUnavailable, attempts[0].attributes["status"])
+	}
+
+	if !request.ended || !signing[0].ended {
+		t.Errorf("expected all spans to be ended")
+	}
+}
+
+func TestTracerRecordsErrors(t *testing.T) {
+	server, _, err := getMockServerWithFileData("testdata/weather_error.json", http.StatusNotFound)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer server.Close()
+
+	tracer := &testTracer{}
+	client := Client{BaseURL: server.URL, Tracer: tracer}
+
+	_, err = client.Weather(context.TODO(), "", WeatherRequest{})
+	if err == nil {
+		t.Fatal("expected request to error")
+	}
+
+	requests := tracer.named(SpanRequest)
+	if len(requests) != 1 || len(requests[0].errors) != 1 {
+		t.Fatalf("expected the request span to record the error, have: %+v", requests)
+	}
+
+	if requests[0].attributes["status"] != http.StatusNotFound {
+		t.Errorf("want: %d, have: %v", http.StatusNotFound, requests[0].attributes["status"])
+	}
+}
+
+func TestTracerRecordsTokenErrors(t *testing.T) {
+	tracer := &testTracer{}
+
+	client := NewCredentialedClient(Credentials{
+		KeyID:      "key",
+		TeamID:     "team",
+		ServiceID:  "service",
+		PrivateKey: []byte("invalid"),
+	}, WithTracer(tracer))
+
+	_, err := client.Weather(context.Background(), WeatherRequest{})
+	if !errors.Is(err, ErrToken) {
+		t.Fatalf("expected a token error, got: %v", err)
+	}
+
+	requests := tracer.named(SpanRequest)
+	if len(requests) != 1 || len(requests[0].errors) != 1 || !requests[0].ended {
+		t.Fatalf("expected an ended request span recording the error, have: %+v", requests)
+	}
+
+	signing := tracer.named(SpanSignToken)
+	if len(signing) != 1 || signing[0].parent != requests[0] || len(signing[0].errors) != 1 {
+		t.Errorf("expected a failed token signing span below the request span, have: %+v", signing)
+	}
+}
+
+func TestTraceParentNotSentByDefault(t *testing.T) {
+	traceParents := make(chan string, 1)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceParents <- r.Header.Get("traceparent")
+		fmt.Fprintln(w, `{}`)
+	}))
+	defer server.Close()
+
+	client := Client{BaseURL: server.URL, Tracer: &testTracer{}}
+
+	_, err := client.Attribution(context.TODO(), AttributionRequest{Language: "en"})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if have := <-traceParents; have != "" {
+		t.Errorf("expected no traceparent header, have: %s", have)
+	}
+}
+
+func TestFormatTraceParent(t *testing.T) {
+	traceID := [16]byte{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36}
+	spanID := [8]byte{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7}
+
+	want := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	if have := FormatTraceParent(traceID, spanID, true); have != want {
+		t.Errorf("want: %s, have: %s", want, have)
+	}
+}
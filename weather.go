@@ -1,6 +1,8 @@
 package weatherkit
 
 import (
+	"encoding/json"
+	"fmt"
 	"net/url"
 	"time"
 )
@@ -49,7 +51,11 @@ type WeatherRequest struct {
 	Timezone string
 }
 
-func (o WeatherRequest) url() string {
+func (o WeatherRequest) endpoint() Endpoint {
+	return EndpointWeather
+}
+
+func (o WeatherRequest) url(base string) string {
 	q := url.Values{}
 
 	if o.CountryCode != "" {
@@ -77,7 +83,7 @@ func (o WeatherRequest) url() string {
 		q.Add("timezone", o.Timezone)
 	}
 
-	return weatherEndpoint(o.Language, o.Latitude, o.Longitude, q)
+	return weatherEndpoint(base, o.Language, o.Latitude, o.Longitude, q)
 }
 
 // WeatherResponse contains all requested properties.
@@ -96,6 +102,78 @@ type WeatherResponse struct {
 
 	// Weather alerts for the requested location.
 	WeatherAlerts *WeatherAlertCollection `json:"weatherAlerts,omitempty"`
+
+	// How the forecast for the requested location compares to historical normals.
+	HistoricalComparisons *HistoricalComparisons `json:"historicalComparisons,omitempty"`
+
+	// Significant changes in the forecast for the requested location.
+	WeatherChanges *WeatherChanges `json:"weatherChanges,omitempty"`
+}
+
+// dataSet returns the block of the response holding the data set along with its expiration time.
+// The block is nil if the response does not include the data set.
+func (r *WeatherResponse) dataSet(dataSet DataSet) (interface{}, *time.Time) {
+	switch dataSet {
+	case DataSetCurrentWeather:
+		if r.CurrentWeather != nil {
+			return r.CurrentWeather, r.CurrentWeather.Metadata.ExpireTime
+		}
+	case DataSetForecastDaily:
+		if r.ForcastDaily != nil {
+			return r.ForcastDaily, r.ForcastDaily.Metadata.ExpireTime
+		}
+	case DataSetForecastHourly:
+		if r.ForcastHourly != nil {
+			return r.ForcastHourly, r.ForcastHourly.Metadata.ExpireTime
+		}
+	case DataSetForecastNextHour:
+		if r.ForcastNextHour != nil {
+			return r.ForcastNextHour, r.ForcastNextHour.Metadata.ExpireTime
+		}
+	case DataSetWeatherAlerts:
+		if r.WeatherAlerts != nil {
+			return r.WeatherAlerts, nil
+		}
+	case DataSetHistoricalComparisons:
+		if r.HistoricalComparisons != nil {
+			return r.HistoricalComparisons, r.HistoricalComparisons.Metadata.ExpireTime
+		}
+	case DataSetWeatherChanges:
+		if r.WeatherChanges != nil {
+			return r.WeatherChanges, r.WeatherChanges.Metadata.ExpireTime
+		}
+	}
+
+	return nil, nil
+}
+
+// setDataSet decodes the JSON encoded data set block into the response.
+func (r *WeatherResponse) setDataSet(dataSet DataSet, data []byte) error {
+	switch dataSet {
+	case DataSetCurrentWeather:
+		r.CurrentWeather = &CurrentWeather{}
+		return json.Unmarshal(data, r.CurrentWeather)
+	case DataSetForecastDaily:
+		r.ForcastDaily = &DailyForecast{}
+		return json.Unmarshal(data, r.ForcastDaily)
+	case DataSetForecastHourly:
+		r.ForcastHourly = &HourlyForecast{}
+		return json.Unmarshal(data, r.ForcastHourly)
+	case DataSetForecastNextHour:
+		r.ForcastNextHour = &NextHourForecast{}
+		return json.Unmarshal(data, r.ForcastNextHour)
+	case DataSetWeatherAlerts:
+		r.WeatherAlerts = &WeatherAlertCollection{}
+		return json.Unmarshal(data, r.WeatherAlerts)
+	case DataSetHistoricalComparisons:
+		r.HistoricalComparisons = &HistoricalComparisons{}
+		return json.Unmarshal(data, r.HistoricalComparisons)
+	case DataSetWeatherChanges:
+		r.WeatherChanges = &WeatherChanges{}
+		return json.Unmarshal(data, r.WeatherChanges)
+	}
+
+	return fmt.Errorf("unknown data set: %s", dataSet)
 }
 
 // PrecipitationType is the type of precipitation forecasted to occur during the day.
@@ -144,6 +222,24 @@ type HourlyForecast struct {
 
 	// The hourly forecast information.
 	Hours []HourWeatherConditions `json:"hours,omitempty"`
+
+	// The JSON fields returned by the API which are not modeled by this type, by key.
+	Extras map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the HourlyForecast, capturing unknown fields into Extras.
+func (f *HourlyForecast) UnmarshalJSON(data []byte) error {
+	type plain HourlyForecast
+	extras, err := unmarshalWithExtras(data, (*plain)(f))
+	f.Extras = extras
+
+	return err
+}
+
+// MarshalJSON encodes the HourlyForecast along with its Extras.
+func (f HourlyForecast) MarshalJSON() ([]byte, error) {
+	type plain HourlyForecast
+	return marshalWithExtras(plain(f), f.Extras)
 }
 
 // HourWeatherConditions contains the historical or forecasted weather conditions for a specified hour.
@@ -151,6 +247,15 @@ type HourWeatherConditions struct {
 	// (Required) The percentage of the sky covered with clouds during the period, from 0 to 1.
 	CloudCover float64 `json:"cloudCover"`
 
+	// The percentage of the sky covered with low altitude clouds during the period, from 0 to 1.
+	CloudCoverLowAltPct float64 `json:"cloudCoverLowAltPct"`
+
+	// The percentage of the sky covered with mid altitude clouds during the period, from 0 to 1.
+	CloudCoverMidAltPct float64 `json:"cloudCoverMidAltPct"`
+
+	// The percentage of the sky covered with high altitude clouds during the period, from 0 to 1.
+	CloudCoverHighAltPct float64 `json:"cloudCoverHighAltPct"`
+
 	// (Required) An enumeration value indicating the condition at the time.
 	ConditionCode string `json:"conditionCode,omitempty"`
 
@@ -166,6 +271,9 @@ type HourWeatherConditions struct {
 	// (Required) The chance of precipitation forecasted to occur during the hour, from 0 to 1.
 	PrecipitationChance float64 `json:"precipitationChance"`
 
+	// (Required) The precipitation intensity, in millimeters per hour.
+	PrecipitationIntensity float64 `json:"precipitationIntensity"`
+
 	// (Required) The type of precipitation forecasted to occur during the period.
 	PrecipitationType PrecipitationType `json:"precipitationType,omitempty"`
 
@@ -175,6 +283,9 @@ type HourWeatherConditions struct {
 	// The direction of change of the sea-level air pressure.
 	PressureTrend PressureTrend `json:"pressureTrend"`
 
+	// The depth of snow as ice crystals forecasted to occur during the hour, in millimeters.
+	SnowfallAmount float64 `json:"snowfallAmount"`
+
 	// The rate at which snow crystals are falling, in millimeters per hour.
 	SnowfallIntensity float64 `json:"snowfallIntensity"`
 
@@ -239,6 +350,24 @@ const (
 type DailyForecast struct {
 	ProductData
 	Days []DayWeatherConditions `json:"days,omitempty"`
+
+	// The JSON fields returned by the API which are not modeled by this type, by key.
+	Extras map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the DailyForecast, capturing unknown fields into Extras.
+func (f *DailyForecast) UnmarshalJSON(data []byte) error {
+	type plain DailyForecast
+	extras, err := unmarshalWithExtras(data, (*plain)(f))
+	f.Extras = extras
+
+	return err
+}
+
+// MarshalJSON encodes the DailyForecast along with its Extras.
+func (f DailyForecast) MarshalJSON() ([]byte, error) {
+	type plain DailyForecast
+	return marshalWithExtras(plain(f), f.Extras)
 }
 
 // DayWeatherConditions contains the historical or forecasted weather conditions for a specified day.
@@ -255,6 +384,12 @@ type DayWeatherConditions struct {
 	// (Required) The starting date and time of the day.
 	ForecastStart *time.Time `json:"forecastStart,omitempty"`
 
+	// The maximum relative humidity during the day, from 0 to 1.
+	HumidityMax float64 `json:"humidityMax"`
+
+	// The minimum relative humidity during the day, from 0 to 1.
+	HumidityMin float64 `json:"humidityMin"`
+
 	// (Required) The maximum ultraviolet index value during the day.
 	MaxUvIndex int64 `json:"maxUvIndex"`
 
@@ -273,12 +408,18 @@ type DayWeatherConditions struct {
 	// (Required) The amount of precipitation forecasted to occur during the day, in millimeters.
 	PrecipitationAmount float64 `json:"precipitationAmount"`
 
+	// The amount of each type of precipitation forecasted to occur during the day.
+	PrecipitationAmountByType *PrecipitationAmountByType `json:"precipitationAmountByType,omitempty"`
+
 	// (Required) The chance of precipitation forecasted to occur during the day.
 	PrecipitationChance float64 `json:"precipitationChance"`
 
 	// (Required) The type of precipitation forecasted to occur during the day.
 	PrecipitationType PrecipitationType `json:"precipitationType,omitempty"`
 
+	// The forecast for the remainder of the day, only present for the current day.
+	RestOfDayForecast *DayPartForecast `json:"restOfDayForecast,omitempty"`
+
 	// (Required) The depth of snow as ice crystals forecasted to occur during the day, in millimeters.
 	SnowfallAmount float64 `json:"snowfallAmount"`
 
@@ -315,8 +456,29 @@ type DayWeatherConditions struct {
 	// (Required) The maximum temperature forecasted to occur during the day, in degrees Celsius.
 	TemperatureMax float64 `json:"temperatureMax"`
 
+	// The time at which the maximum temperature occurs during the day.
+	TemperatureMaxTime *time.Time `json:"temperatureMaxTime,omitempty"`
+
 	// (Required) The minimum temperature forecasted to occur during the day, in degrees Celsius.
 	TemperatureMin float64 `json:"temperatureMin"`
+
+	// The time at which the minimum temperature occurs during the day.
+	TemperatureMinTime *time.Time `json:"temperatureMinTime,omitempty"`
+
+	// The maximum distance at which terrain is visible during the day, in meters.
+	VisibilityMax float64 `json:"visibilityMax"`
+
+	// The minimum distance at which terrain is visible during the day, in meters.
+	VisibilityMin float64 `json:"visibilityMin"`
+
+	// The maximum wind gust speed during the day, in kilometers per hour.
+	WindGustSpeedMax float64 `json:"windGustSpeedMax"`
+
+	// The average wind speed during the day, in kilometers per hour.
+	WindSpeedAvg float64 `json:"windSpeedAvg"`
+
+	// The maximum wind speed during the day, in kilometers per hour.
+	WindSpeedMax float64 `json:"windSpeedMax"`
 }
 
 // DayPartForecast is a summary forecast for a daytime or overnight period.
@@ -324,6 +486,15 @@ type DayPartForecast struct {
 	// (Required) The percentage of the sky covered with clouds during the period, from 0 to 1.
 	CloudCover float64 `json:"cloudCover"`
 
+	// The percentage of the sky covered with low altitude clouds during the period, from 0 to 1.
+	CloudCoverLowAltPct float64 `json:"cloudCoverLowAltPct"`
+
+	// The percentage of the sky covered with mid altitude clouds during the period, from 0 to 1.
+	CloudCoverMidAltPct float64 `json:"cloudCoverMidAltPct"`
+
+	// The percentage of the sky covered with high altitude clouds during the period, from 0 to 1.
+	CloudCoverHighAltPct float64 `json:"cloudCoverHighAltPct"`
+
 	// (Required) An enumeration value indicating the condition at the time.
 	ConditionCode string `json:"conditionCode,omitempty"`
 
@@ -336,9 +507,18 @@ type DayPartForecast struct {
 	// (Required) The relative humidity during the period, from 0 to 1.
 	Humidity float64 `json:"humidity"`
 
+	// The maximum relative humidity during the period, from 0 to 1.
+	HumidityMax float64 `json:"humidityMax"`
+
+	// The minimum relative humidity during the period, from 0 to 1.
+	HumidityMin float64 `json:"humidityMin"`
+
 	// (Required) The amount of precipitation forecasted to occur during the period, in millimeters.
 	PrecipitationAmount float64 `json:"precipitationAmount"`
 
+	// The amount of each type of precipitation forecasted to occur during the period.
+	PrecipitationAmountByType *PrecipitationAmountByType `json:"precipitationAmountByType,omitempty"`
+
 	// (Required) The chance of precipitation forecasted to occur during the period.
 	PrecipitationChance float64 `json:"precipitationChance"`
 
@@ -348,17 +528,74 @@ type DayPartForecast struct {
 	// (Required) The depth of snow as ice crystals forecasted to occur during the period, in millimeters.
 	SnowfallAmount float64 `json:"snowfallAmount"`
 
+	// The maximum temperature forecasted to occur during the period, in degrees Celsius.
+	TemperatureMax float64 `json:"temperatureMax"`
+
+	// The minimum temperature forecasted to occur during the period, in degrees Celsius.
+	TemperatureMin float64 `json:"temperatureMin"`
+
+	// The maximum distance at which terrain is visible during the period, in meters.
+	VisibilityMax float64 `json:"visibilityMax"`
+
+	// The minimum distance at which terrain is visible during the period, in meters.
+	VisibilityMin float64 `json:"visibilityMin"`
+
 	// The direction the wind is forecasted to come from during the period, in degrees.
 	WindDirection float64 `json:"windDirection"`
 
+	// The maximum wind gust speed forecasted during the period, in kilometers per hour.
+	WindGustSpeedMax float64 `json:"windGustSpeedMax"`
+
 	// (Required) The average speed the wind is forecasted to be during the period, in kilometers per hour.
 	WindSpeed float64 `json:"windSpeed"`
+
+	// The maximum wind speed forecasted during the period, in kilometers per hour.
+	WindSpeedMax float64 `json:"windSpeedMax"`
+}
+
+// PrecipitationAmountByType is the amount of precipitation forecasted for each type of precipitation, in millimeters.
+type PrecipitationAmountByType struct {
+	// The amount of hail.
+	Hail float64 `json:"hail"`
+
+	// The amount of mixed precipitation.
+	Mixed float64 `json:"mixed"`
+
+	// The amount of precipitation of an unknown type.
+	Precipitation float64 `json:"precipitation"`
+
+	// The amount of rain.
+	Rain float64 `json:"rain"`
+
+	// The amount of sleet.
+	Sleet float64 `json:"sleet"`
+
+	// The amount of snow.
+	Snow float64 `json:"snow"`
 }
 
 // NextHourForecast is a minute-by-minute forecast for the next hour.
 type NextHourForecast struct {
 	ProductData
 	NextHourForecastData
+
+	// The JSON fields returned by the API which are not modeled by this type, by key.
+	Extras map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the NextHourForecast, capturing unknown fields into Extras.
+func (f *NextHourForecast) UnmarshalJSON(data []byte) error {
+	type plain NextHourForecast
+	extras, err := unmarshalWithExtras(data, (*plain)(f))
+	f.Extras = extras
+
+	return err
+}
+
+// MarshalJSON encodes the NextHourForecast along with its Extras.
+func (f NextHourForecast) MarshalJSON() ([]byte, error) {
+	type plain NextHourForecast
+	return marshalWithExtras(plain(f), f.Extras)
 }
 
 // NextHourForecastData is the next hour forecast information.
@@ -410,6 +647,24 @@ type ForecastPeriodSummary struct {
 type CurrentWeather struct {
 	ProductData
 	CurrentWeatherData
+
+	// The JSON fields returned by the API which are not modeled by this type, by key.
+	Extras map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the CurrentWeather, capturing unknown fields into Extras.
+func (w *CurrentWeather) UnmarshalJSON(data []byte) error {
+	type plain CurrentWeather
+	extras, err := unmarshalWithExtras(data, (*plain)(w))
+	w.Extras = extras
+
+	return err
+}
+
+// MarshalJSON encodes the CurrentWeather along with its Extras.
+func (w CurrentWeather) MarshalJSON() ([]byte, error) {
+	type plain CurrentWeather
+	return marshalWithExtras(plain(w), w.Extras)
 }
 
 // CurrentWeatherData is the current weather object.
@@ -463,6 +718,157 @@ type CurrentWeatherData struct {
 	WindSpeed float64 `json:"windSpeed"`
 }
 
+// HistoricalComparisons compares the forecast for the requested location to historical normals.
+type HistoricalComparisons struct {
+	ProductData
+
+	// (Required) The comparisons of forecasted conditions to their historical baselines.
+	Comparisons []HistoricalComparison `json:"comparisons,omitempty"`
+
+	// The JSON fields returned by the API which are not modeled by this type, by key.
+	Extras map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the HistoricalComparisons, capturing unknown fields into Extras.
+func (h *HistoricalComparisons) UnmarshalJSON(data []byte) error {
+	type plain HistoricalComparisons
+	extras, err := unmarshalWithExtras(data, (*plain)(h))
+	h.Extras = extras
+
+	return err
+}
+
+// MarshalJSON encodes the HistoricalComparisons along with its Extras.
+func (h HistoricalComparisons) MarshalJSON() ([]byte, error) {
+	type plain HistoricalComparisons
+	return marshalWithExtras(plain(h), h.Extras)
+}
+
+// ComparisonCondition is the weather condition compared to its historical baseline.
+type ComparisonCondition string
+
+const (
+	// The maximum temperature of the day.
+	ComparisonConditionTemperatureMax ComparisonCondition = "temperatureMax"
+
+	// The minimum temperature of the day.
+	ComparisonConditionTemperatureMin ComparisonCondition = "temperatureMin"
+
+	// The amount of precipitation during the day.
+	ComparisonConditionPrecipitationAmount ComparisonCondition = "precipitationAmount"
+
+	// The amount of snowfall during the day.
+	ComparisonConditionSnowfallAmount ComparisonCondition = "snowfallAmount"
+)
+
+// Deviation is how far a forecasted value is from its historical baseline.
+type Deviation string
+
+const (
+	// The value is much higher than normal.
+	DeviationMuchHigher Deviation = "muchHigher"
+
+	// The value is higher than normal.
+	DeviationHigher Deviation = "higher"
+
+	// The value is normal.
+	DeviationNormal Deviation = "normal"
+
+	// The value is lower than normal.
+	DeviationLower Deviation = "lower"
+
+	// The value is much lower than normal.
+	DeviationMuchLower Deviation = "muchLower"
+)
+
+// HistoricalComparison compares a forecasted condition to its historical baseline.
+type HistoricalComparison struct {
+	// (Required) The statistic the baseline value is computed with, such as mean.
+	BaselineType string `json:"baselineType,omitempty"`
+
+	// (Required) The start date of the historical data the baseline is computed from.
+	BaselineStartDate *time.Time `json:"baselineStartDate,omitempty"`
+
+	// (Required) The historical baseline value, in the units of the condition.
+	BaselineValue float64 `json:"baselineValue"`
+
+	// (Required) The compared condition.
+	Condition ComparisonCondition `json:"condition,omitempty"`
+
+	// (Required) The forecasted value, in the units of the condition.
+	CurrentValue float64 `json:"currentValue"`
+
+	// (Required) How far the forecasted value is from the baseline.
+	Deviation Deviation `json:"deviation,omitempty"`
+}
+
+// WeatherChanges describes significant changes in the forecast for the requested location.
+type WeatherChanges struct {
+	ProductData
+
+	// (Required) The changes, one for each forecasted day.
+	Changes []WeatherChange `json:"changes,omitempty"`
+
+	// (Required) The ending date and time of the forecast.
+	ForecastEnd *time.Time `json:"forecastEnd,omitempty"`
+
+	// (Required) The starting date and time of the forecast.
+	ForecastStart *time.Time `json:"forecastStart,omitempty"`
+
+	// The JSON fields returned by the API which are not modeled by this type, by key.
+	Extras map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the WeatherChanges, capturing unknown fields into Extras.
+func (w *WeatherChanges) UnmarshalJSON(data []byte) error {
+	type plain WeatherChanges
+	extras, err := unmarshalWithExtras(data, (*plain)(w))
+	w.Extras = extras
+
+	return err
+}
+
+// MarshalJSON encodes the WeatherChanges along with its Extras.
+func (w WeatherChanges) MarshalJSON() ([]byte, error) {
+	type plain WeatherChanges
+	return marshalWithExtras(plain(w), w.Extras)
+}
+
+// ChangeTrend is the direction a forecasted condition changes in compared to the previous day.
+type ChangeTrend string
+
+const (
+	// The condition increases.
+	ChangeTrendIncrease ChangeTrend = "increase"
+
+	// The condition decreases.
+	ChangeTrendDecrease ChangeTrend = "decrease"
+
+	// The condition remains about the same.
+	ChangeTrendSteady ChangeTrend = "steady"
+)
+
+// WeatherChange describes how the forecast for a day changes compared to the previous day.
+type WeatherChange struct {
+	// (Required) The change in the amount of precipitation during the day.
+	DayPrecipitationChange ChangeTrend `json:"dayPrecipitationChange,omitempty"`
+
+	// (Required) The ending date and time of the day.
+	ForecastEnd *time.Time `json:"forecastEnd,omitempty"`
+
+	// (Required) The starting date and time of the day.
+	ForecastStart *time.Time `json:"forecastStart,omitempty"`
+
+	// (Required) The change in the maximum temperature.
+	MaxTemperatureChange ChangeTrend `json:"maxTemperatureChange,omitempty"`
+
+	// (Required) The change in the minimum temperature.
+	MinTemperatureChange ChangeTrend `json:"minTemperatureChange,omitempty"`
+
+	// (Required) The change in the amount of precipitation during the night.
+	NightPrecipitationChange ChangeTrend `json:"nightPrecipitationChange,omitempty"`
+}
+
 // WeatherAlertCollection is a collection of weather alerts.
 type WeatherAlertCollection struct {
 	// (Required) An array of weather alert summaries.
@@ -613,6 +1019,24 @@ type WeatherAlertSummary struct {
 
 	// An indication of urgency of action from the reporting agency.
 	Urgency Urgency `json:"urgency,omitempty"`
+
+	// The JSON fields returned by the API which are not modeled by this type, by key.
+	Extras map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the WeatherAlertSummary, capturing unknown fields into Extras.
+func (a *WeatherAlertSummary) UnmarshalJSON(data []byte) error {
+	type plain WeatherAlertSummary
+	extras, err := unmarshalWithExtras(data, (*plain)(a))
+	a.Extras = extras
+
+	return err
+}
+
+// MarshalJSON encodes the WeatherAlertSummary along with its Extras.
+func (a WeatherAlertSummary) MarshalJSON() ([]byte, error) {
+	type plain WeatherAlertSummary
+	return marshalWithExtras(plain(a), a.Extras)
 }
 
 // ProductData is a base type for all weather data.
@@ -624,6 +1048,10 @@ type ProductData struct {
 	Metadata Metadata `json:"metadata,omitempty"`
 }
 
+func (p ProductData) metadata() Metadata {
+	return p.Metadata
+}
+
 // UnitsSystem is the system of units that the weather data is reported in.
 type UnitsSystem string
 
@@ -669,4 +1097,22 @@ type Metadata struct {
 
 	// (Required) The data format version.
 	Version int `json:"version"`
+
+	// The JSON fields returned by the API which are not modeled by this type, by key.
+	Extras map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the Metadata, capturing unknown fields into Extras.
+func (m *Metadata) UnmarshalJSON(data []byte) error {
+	type plain Metadata
+	extras, err := unmarshalWithExtras(data, (*plain)(m))
+	m.Extras = extras
+
+	return err
+}
+
+// MarshalJSON encodes the Metadata along with its Extras.
+func (m Metadata) MarshalJSON() ([]byte, error) {
+	type plain Metadata
+	return marshalWithExtras(plain(m), m.Extras)
 }
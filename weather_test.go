@@ -1,6 +1,10 @@
 package weatherkit
 
 import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
 	"testing"
 	"time"
 )
@@ -28,10 +32,200 @@ func TestWeatherRequestFullUrlGeneration(t *testing.T) {
 		},
 	}
 
-	want := BaseUrl + "/api/v1/weather/en/40.713/-74.006?countryCode=US&currentAsOf=2022-07-10T06%3A14%3A11Z&dailyEnd=2022-07-10T06%3A14%3A11Z&dailyStart=2022-07-10T06%3A14%3A11Z&dataSets=currentWeather%2CforecastDaily%2CforecastHourly%2CforecastNextHour%2CweatherAlerts&hourlyEnd=2022-07-10T06%3A14%3A11Z&hourlyStart=2022-07-10T06%3A14%3A11Z&timezone=America%2FNew_York"
-	have := req.url()
+	want := DefaultBaseURL + "/api/v1/weather/en/40.713/-74.006?countryCode=US&currentAsOf=2022-07-10T06%3A14%3A11Z&dailyEnd=2022-07-10T06%3A14%3A11Z&dailyStart=2022-07-10T06%3A14%3A11Z&dataSets=currentWeather%2CforecastDaily%2CforecastHourly%2CforecastNextHour%2CweatherAlerts&hourlyEnd=2022-07-10T06%3A14%3A11Z&hourlyStart=2022-07-10T06%3A14%3A11Z&timezone=America%2FNew_York"
+	have := req.url(DefaultBaseURL)
 
 	if want != have {
 		t.Errorf("want: %s, have: %s", want, have)
 	}
 }
+
+func TestDailyForecastFields(t *testing.T) {
+	response := readWeatherFixture(t, "testdata/forecast_daily.json")
+
+	days := response.ForcastDaily.Days
+	day := days[0]
+
+	assertFloats(t, map[string][2]float64{
+		"humidityMax":                    {day.HumidityMax, 0.95},
+		"humidityMin":                    {day.HumidityMin, 0.45},
+		"visibilityMax":                  {day.VisibilityMax, 33207.48},
+		"visibilityMin":                  {day.VisibilityMin, 9796.8},
+		"windGustSpeedMax":               {day.WindGustSpeedMax, 29.48},
+		"windSpeedAvg":                   {day.WindSpeedAvg, 16.88},
+		"windSpeedMax":                   {day.WindSpeedMax, 21.68},
+		"precipitationAmountByType.rain": {day.PrecipitationAmountByType.Rain, 2.77},
+		"precipitationAmountByType.snow": {day.PrecipitationAmountByType.Snow, 0},
+	})
+
+	assertTime(t, "temperatureMaxTime", day.TemperatureMaxTime, "2022-07-05T19:00:00Z")
+	assertTime(t, "temperatureMinTime", day.TemperatureMinTime, "2022-07-05T10:00:00Z")
+
+	if day.RestOfDayForecast == nil {
+		t.Fatal("expected the rest of day forecast for the current day")
+	}
+
+	assertTime(t, "restOfDayForecast.forecastStart", day.RestOfDayForecast.ForecastStart, "2022-07-05T14:00:00Z")
+	assertTime(t, "restOfDayForecast.forecastEnd", day.RestOfDayForecast.ForecastEnd, "2022-07-06T04:00:00Z")
+
+	if days[1].RestOfDayForecast != nil {
+		t.Errorf("expected no rest of day forecast for the following days")
+	}
+}
+
+func TestDayPartForecastFields(t *testing.T) {
+	response := readWeatherFixture(t, "testdata/forecast_daily.json")
+
+	part := response.ForcastDaily.Days[0].DaytimeForecast
+
+	assertFloats(t, map[string][2]float64{
+		"cloudCoverLowAltPct":            {part.CloudCoverLowAltPct, 0.21},
+		"cloudCoverMidAltPct":            {part.CloudCoverMidAltPct, 0.16},
+		"cloudCoverHighAltPct":           {part.CloudCoverHighAltPct, 0.27},
+		"humidityMax":                    {part.HumidityMax, 0.64},
+		"humidityMin":                    {part.HumidityMin, 0.45},
+		"temperatureMax":                 {part.TemperatureMax, 26.27},
+		"temperatureMin":                 {part.TemperatureMin, 22.67},
+		"visibilityMax":                  {part.VisibilityMax, 33207.48},
+		"visibilityMin":                  {part.VisibilityMin, 9796.8},
+		"windGustSpeedMax":               {part.WindGustSpeedMax, 27.91},
+		"windSpeedMax":                   {part.WindSpeedMax, 20.53},
+		"precipitationAmountByType.rain": {part.PrecipitationAmountByType.Rain, 0.23},
+	})
+}
+
+func TestHourlyForecastFields(t *testing.T) {
+	response := readWeatherFixture(t, "testdata/forecast_hourly.json")
+
+	hour := response.ForcastHourly.Hours[6]
+
+	assertFloats(t, map[string][2]float64{
+		"cloudCoverLowAltPct":    {hour.CloudCoverLowAltPct, 0.37},
+		"cloudCoverMidAltPct":    {hour.CloudCoverMidAltPct, 0.28},
+		"cloudCoverHighAltPct":   {hour.CloudCoverHighAltPct, 0.46},
+		"precipitationIntensity": {hour.PrecipitationIntensity, 0.76},
+	})
+
+	snow := HourWeatherConditions{}
+
+	err := json.Unmarshal([]byte(`{"precipitationType": "snow", "snowfallAmount": 1.25, "snowfallIntensity": 1.25}`), &snow)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	assertFloats(t, map[string][2]float64{
+		"snowfallAmount": {snow.SnowfallAmount, 1.25},
+	})
+}
+
+func readWeatherFixture(t *testing.T, filename string) WeatherResponse {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	response := WeatherResponse{}
+
+	err = json.Unmarshal(data, &response)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	return response
+}
+
+// assertFloats compares pairs of decoded and expected values by field name.
+func assertFloats(t *testing.T, fields map[string][2]float64) {
+	t.Helper()
+
+	for name, values := range fields {
+		if values[0] != values[1] {
+			t.Errorf("%s want: %v, have: %v", name, values[1], values[0])
+		}
+	}
+}
+
+func assertTime(t *testing.T, name string, have *time.Time, want string) {
+	t.Helper()
+
+	if have == nil || have.Format(time.RFC3339) != want {
+		t.Errorf("%s want: %s, have: %v", name, want, have)
+	}
+}
+
+func TestHistoricalComparisonsFields(t *testing.T) {
+	response := readWeatherFixture(t, "testdata/historical_comparisons.json")
+
+	comparisons := response.HistoricalComparisons.Comparisons
+	if len(comparisons) != 3 {
+		t.Fatalf("want: %d comparisons, have: %d", 3, len(comparisons))
+	}
+
+	comparison := comparisons[0]
+	if comparison.Condition != ComparisonConditionTemperatureMax || comparison.Deviation != DeviationHigher || comparison.BaselineType != "mean" {
+		t.Errorf("unexpected comparison: %+v", comparison)
+	}
+
+	assertFloats(t, map[string][2]float64{
+		"currentValue":  {comparison.CurrentValue, 29.77},
+		"baselineValue": {comparison.BaselineValue, 28.41},
+	})
+
+	assertTime(t, "baselineStartDate", comparison.BaselineStartDate, "1991-01-01T00:00:00Z")
+}
+
+func TestWeatherChangesFields(t *testing.T) {
+	response := readWeatherFixture(t, "testdata/weather_changes.json")
+
+	changes := response.WeatherChanges
+	assertTime(t, "forecastStart", changes.ForecastStart, "2022-07-05T04:00:00Z")
+	assertTime(t, "forecastEnd", changes.ForecastEnd, "2022-07-07T04:00:00Z")
+
+	if len(changes.Changes) != 2 {
+		t.Fatalf("want: %d changes, have: %d", 2, len(changes.Changes))
+	}
+
+	change := changes.Changes[1]
+	if change.MaxTemperatureChange != ChangeTrendDecrease || change.MinTemperatureChange != ChangeTrendSteady ||
+		change.DayPrecipitationChange != ChangeTrendDecrease || change.NightPrecipitationChange != ChangeTrendDecrease {
+		t.Errorf("unexpected change: %+v", change)
+	}
+
+	assertTime(t, "changes[1].forecastStart", change.ForecastStart, "2022-07-06T04:00:00Z")
+}
+
+func TestTrendDataSetsWithForecasts(t *testing.T) {
+	combined := map[string]json.RawMessage{}
+
+	for _, fixture := range []string{"testdata/forecast_daily.json", "testdata/historical_comparisons.json", "testdata/weather_changes.json"} {
+		data, err := ioutil.ReadFile(fixture)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		err = json.Unmarshal(data, &combined)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+
+	data, err := json.Marshal(combined)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	server := getMockServer(data, http.StatusOK)
+	defer server.Close()
+
+	client := Client{BaseURL: server.URL}
+	dataSets := DataSets{DataSetForecastDaily, DataSetHistoricalComparisons, DataSetWeatherChanges}
+
+	response, err := client.Weather(context.TODO(), "", WeatherRequest{DataSets: dataSets})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if err := response.Unavailable(dataSets); err != nil {
+		t.Errorf("expected every data set in the response, got: %v", err)
+	}
+}